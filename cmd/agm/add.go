@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+func runAdd(c *command, args []string) error {
+	fs := c.flagSet()
+	var wanted stringList
+	fs.Var(&wanted, "skill", "Import skill `name` from a folder of skills (repeatable)")
+	all := fs.Bool("all", false, "Import every skill found in a folder of skills")
+	force := fs.Bool("force", false, "Overwrite skills that are already installed")
+	sources, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return &usageError{cmd: c.name, msg: "missing <url|path>"}
+	}
+	if *all && len(wanted) > 0 {
+		return &usageError{cmd: c.name, msg: "--skill and --all are mutually exclusive"}
+	}
+
+	wantSet := make(map[string]bool)
+	for _, name := range wanted {
+		wantSet[name] = true
+	}
+	matched := make(map[string]bool)
+	selectCalled := false
+
+	opts := commands.AddOptions{
+		Select: func(found []commands.Candidate) ([]string, error) {
+			selectCalled = true
+			var names []string
+			for _, cand := range found {
+				if *all || wantSet[cand.Name] {
+					names = append(names, cand.Name)
+					matched[cand.Name] = true
+				}
+			}
+			if !*all && len(wantSet) == 0 {
+				var available []string
+				for _, cand := range found {
+					available = append(available, cand.Name)
+				}
+				return nil, &usageError{cmd: c.name, msg: fmt.Sprintf(
+					"source contains %d skills (%s); choose with --skill <name> or --all",
+					len(found), strings.Join(available, ", "))}
+			}
+			return names, nil
+		},
+		Overwrite: func(id string) bool {
+			if !*force {
+				fmt.Println(tui.MutedText.Render("Skipped " + id + " (already installed, use --force to overwrite)"))
+			}
+			return *force
+		},
+	}
+
+	// Every source is tried, so one bad source does not hide the rest.
+	var errs []error
+	for _, source := range sources {
+		if _, err := commands.Add(source, opts); err != nil {
+			errs = append(errs, err)
+			if len(sources) > 1 {
+				fmt.Fprintln(os.Stderr, tui.RenderError(source+": "+err.Error()))
+			}
+		}
+	}
+	switch {
+	case len(errs) == 1 && len(sources) == 1:
+		return errs[0]
+	case len(errs) > 0:
+		return commands.AggregateError(errs, "%d of %d source(s) could not be added", len(errs), len(sources))
+	}
+
+	// --skill only filters folders of skills; a URL to a single skill ignores it.
+	if !selectCalled {
+		return nil
+	}
+	var missing []string
+	for _, name := range wanted {
+		if !matched[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return &commands.Error{Kind: commands.KindNotFound, Msg: "skill(s) not found in source: " + strings.Join(missing, ", ")}
+	}
+	return nil
+}
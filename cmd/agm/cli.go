@@ -0,0 +1,280 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// command is a scriptable agm subcommand.
+type command struct {
+	name    string
+	aliases []string
+	args    string // positional argument synopsis, e.g. "<skill-id>..."
+	summary string
+	run     func(c *command, args []string) error
+	// complete returns candidates for positional arguments; nil completes files.
+	complete func() []string
+}
+
+// setVerbose turns git tracing on or off.
+func setVerbose(on bool) {
+	if on {
+		git.SetTrace(os.Stderr)
+	} else {
+		git.SetTrace(nil)
+	}
+}
+
+// traceFromEnv reports whether AGM_TRACE asks for verbose output.
+func traceFromEnv() bool {
+	v := os.Getenv("AGM_TRACE")
+	if v == "" {
+		return false
+	}
+	on, err := strconv.ParseBool(v)
+	return err != nil || on
+}
+
+// gitTimeout returns the per-command git timeout from AGM_GIT_TIMEOUT or the
+// gitTimeout setting in config.json.
+func gitTimeout() (time.Duration, error) {
+	v := os.Getenv("AGM_GIT_TIMEOUT")
+	if v == "" {
+		// An unreadable config is reported by the commands that need it.
+		if cfg, err := config.Load(); err == nil {
+			v = cfg.GitTimeout
+		}
+	}
+	if v == "" {
+		return git.DefaultTimeout, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, &commands.Error{Kind: commands.KindConfig, Msg: fmt.Sprintf("invalid git timeout %q: use a duration such as 90s or 5m, or 0 for none", v)}
+	}
+	return d, nil
+}
+
+// errHelp is returned when the user asked for a command's help text.
+var errHelp = errors.New("help requested")
+
+// usageError reports invalid command-line input.
+type usageError struct {
+	cmd string
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// flagSet returns a flag set for the command that reports errors to the caller
+// instead of printing them.
+func (c *command) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("agm "+c.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	fs.BoolFunc("verbose", "Log every git command, its duration and stderr to stderr", func(v string) error {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		setVerbose(on)
+		return nil
+	})
+	return fs
+}
+
+// describing, when set, receives the command's flag set from parse, which then
+// returns errHelp without parsing. Completion uses it to list a command's flags.
+var describing func(fs *flag.FlagSet)
+
+// parse parses flags and positional arguments in any order.
+// Everything after a "--" terminator is treated as positional.
+func (c *command) parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	if describing != nil {
+		describing(fs)
+		return nil, errHelp
+	}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				c.printUsage(fs)
+				return nil, errHelp
+			}
+			return nil, &usageError{cmd: c.name, msg: err.Error()}
+		}
+		rest := fs.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// parseNone parses flags for a command that takes no positional arguments.
+func (c *command) parseNone(fs *flag.FlagSet, args []string) error {
+	rest, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		return &usageError{cmd: c.name, msg: "unexpected argument: " + rest[0]}
+	}
+	return nil
+}
+
+// parseSkillArgs parses flags and resolves at least one skill argument to skill IDs.
+func parseSkillArgs(c *command, fs *flag.FlagSet, args []string) ([]string, error) {
+	names, err := c.parse(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, &usageError{cmd: c.name, msg: "missing <skill-id>"}
+	}
+	return commands.ResolveSkillIDs(names)
+}
+
+// addOutputFlag registers --output and its -o shorthand on fs.
+func addOutputFlag(fs *flag.FlagSet) *string {
+	format := fs.String("output", output.FormatText, "Output `format`: text or json")
+	fs.StringVar(format, "o", output.FormatText, "Shorthand for --output")
+	return format
+}
+
+// jsonOutput validates an --output value. For JSON it moves human-readable
+// messages to stderr so that stdout carries only the document.
+func (c *command) jsonOutput(format string) (bool, error) {
+	f, err := output.ParseFormat(format)
+	if err != nil {
+		return false, &usageError{cmd: c.name, msg: err.Error()}
+	}
+	if f != output.FormatJSON {
+		return false, nil
+	}
+	commands.SetOutput(os.Stderr)
+	commands.SetProgress(func(e commands.ProgressEvent) {
+		output.WriteJSONLine(os.Stderr, "progress", e)
+	})
+	return true, nil
+}
+
+// showProgress draws progress bars below the regular output when stdout is a
+// terminal.
+func showProgress() {
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return
+	}
+	bar := tui.NewProgress(os.Stdout)
+	commands.SetOutput(bar)
+	commands.SetProgress(func(e commands.ProgressEvent) {
+		if e.Done {
+			bar.Done()
+			return
+		}
+		bar.Set(progressLabel(e), e.Percent)
+	})
+}
+
+// progressLabel describes a progress event, e.g.
+// "Receiving objects 450/1000 · registry".
+func progressLabel(e commands.ProgressEvent) string {
+	label := e.Phase
+	switch {
+	case e.Total > 0:
+		label += fmt.Sprintf(" %d/%d", e.Current, e.Total)
+	case e.Current > 0:
+		label += fmt.Sprintf(" %d", e.Current)
+	}
+	return label + tui.MutedText.Render(" · "+e.Subject)
+}
+
+// addDryRunFlag registers --dry-run and its -n shorthand on fs.
+func addDryRunFlag(fs *flag.FlagSet) *bool {
+	dryRun := fs.Bool("dry-run", false, "Print the change plan without touching disk")
+	fs.BoolVar(dryRun, "n", false, "Shorthand for --dry-run")
+	return dryRun
+}
+
+// writePlan prints a dry-run plan as text or as a JSON document.
+func writePlan(plan *commands.Plan, asJSON bool) error {
+	if asJSON {
+		return output.WriteJSON(os.Stdout, "plan", plan)
+	}
+	plan.Print()
+	return nil
+}
+
+// printUsage writes the command's help text to stdout.
+func (c *command) printUsage(fs *flag.FlagSet) {
+	synopsis := "agm " + c.name
+	if c.args != "" {
+		synopsis += " " + c.args
+	}
+	hasFlags := false
+	fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		synopsis += " [flags]"
+	}
+
+	fmt.Println("Usage: " + synopsis)
+	fmt.Println()
+	fmt.Println("  " + c.summary)
+	if len(c.aliases) > 0 {
+		fmt.Println()
+		fmt.Println("Aliases: " + strings.Join(c.aliases, ", "))
+	}
+	if !hasFlags {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Flags:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fs.VisitAll(func(f *flag.Flag) {
+		name := "-" + f.Name
+		if len(f.Name) > 1 {
+			name = "-" + name
+		}
+		typ, usage := flag.UnquoteUsage(f)
+		if typ != "" {
+			name += " " + typ
+		}
+		if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "[]" {
+			usage += fmt.Sprintf(" (default %s)", f.DefValue)
+		}
+		fmt.Fprintf(w, "  %s\t%s\n", name, usage)
+	})
+	w.Flush()
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+)
+
+// completeCommand is the hidden command the completion scripts call.
+// It prints one candidate per line for the last word of its arguments.
+const completeCommand = "__complete"
+
+// completeFiles tells the completion scripts to fall back to file completion.
+const completeFiles = ":files"
+
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+func runCompletion(c *command, args []string) error {
+	fs := c.flagSet()
+	shells, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(shells) != 1 {
+		return &usageError{cmd: c.name, msg: "expected one of: bash, zsh, fish"}
+	}
+	script, ok := completionScripts[shells[0]]
+	if !ok {
+		return &usageError{cmd: c.name, msg: "unsupported shell " + shells[0] + " (expected bash, zsh or fish)"}
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// completeNone is used by commands that take no positional arguments.
+func completeNone() []string {
+	return nil
+}
+
+func completeShells() []string {
+	return []string{"bash", "fish", "zsh"}
+}
+
+func completeHookActions() []string {
+	return []string{"install", "uninstall"}
+}
+
+func completeIgnoreModes() []string {
+	return []string{"exclude", "gitignore", "off"}
+}
+
+func completeLinkStrategies() []string {
+	return []string{"absolute", "home", "relative"}
+}
+
+// completeSkillIDs returns the IDs of all installed skills.
+func completeSkillIDs() []string {
+	entries, err := commands.ListSkills(nil)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+// completeTools returns the configured AI tool types.
+func completeTools() []string {
+	var types []string
+	for _, tool := range project.NewDetector("").Tools() {
+		types = append(types, tool.Type)
+	}
+	return types
+}
+
+// flagValues completes the values of flags that take a fixed set of values.
+var flagValues = map[string]func() []string{
+	"tool":   completeTools,
+	"output": func() []string { return []string{output.FormatText, output.FormatJSON} },
+	"o":      func() []string { return []string{output.FormatText, output.FormatJSON} },
+}
+
+// complete returns completion candidates for the last of words, which are the
+// command-line arguments after "agm".
+func complete(words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		return withPrefix(commandNames(), cur)
+	}
+
+	if words[0] == "help" {
+		if len(words) == 2 {
+			return withPrefix(commandNames(), cur)
+		}
+		return nil
+	}
+	c := findCommand(words[0])
+	if c == nil {
+		return nil
+	}
+	fs := flagsOf(c)
+
+	// The value of a flag, given as --flag=value or as the next word.
+	if name, value, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(name, "-") {
+		return flagValueCandidates(fs, strings.TrimLeft(name, "-"), name+"=", value)
+	}
+	if prev := words[len(words)-2]; strings.HasPrefix(prev, "-") && !strings.Contains(prev, "=") && !slices.Contains(words[1:len(words)-1], "--") {
+		if f := fs.Lookup(strings.TrimLeft(prev, "-")); f != nil && !isBoolFlag(f) {
+			return flagValueCandidates(fs, f.Name, "", cur)
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			if len(f.Name) == 1 {
+				names = append(names, "-"+f.Name)
+			} else {
+				names = append(names, "--"+f.Name)
+			}
+		})
+		return withPrefix(names, cur)
+	}
+
+	if c.complete == nil {
+		return []string{completeFiles}
+	}
+	return withPrefix(c.complete(), cur)
+}
+
+func flagValueCandidates(fs *flag.FlagSet, name, prefix, value string) []string {
+	if fs.Lookup(name) == nil {
+		return nil
+	}
+	values, ok := flagValues[name]
+	if !ok {
+		return []string{completeFiles}
+	}
+	var out []string
+	for _, v := range withPrefix(values(), value) {
+		out = append(out, prefix+v)
+	}
+	return out
+}
+
+// flagsOf returns the flags c accepts, without running the command.
+func flagsOf(c *command) *flag.FlagSet {
+	var fs *flag.FlagSet
+	describing = func(f *flag.FlagSet) { fs = f }
+	defer func() { describing = nil }()
+	c.run(c, nil)
+	if fs == nil {
+		fs = c.flagSet()
+	}
+	return fs
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func commandNames() []string {
+	names := []string{"help"}
+	for _, c := range subcommands {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var out []string
+	for _, s := range candidates {
+		if strings.HasPrefix(s, prefix) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func runComplete(args []string) int {
+	for _, s := range complete(args) {
+		fmt.Fprintln(os.Stdout, s)
+	}
+	return exitOK
+}
+
+const bashCompletion = `# bash completion for agm
+# Load with: source <(agm completion bash)
+
+_agm() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -ra words <<< "$line"
+    [[ "$line" == *" " ]] && words+=("")
+    local cur="${words[${#words[@]}-1]}"
+
+    local out
+    out=$(agm __complete "${words[@]:1}" 2>/dev/null)
+    if [[ "$out" == ":files" ]]; then
+        COMPREPLY=($(compgen -f -- "$cur"))
+        return
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($out)
+
+    # bash splits words at colons; trim what is already typed before the last one.
+    if [[ "$cur" == *:* && "$COMP_WORDBREAKS" == *:* ]]; then
+        local colon="${cur%"${cur##*:}"}"
+        local i=${#COMPREPLY[@]}
+        while ((i-- > 0)); do
+            COMPREPLY[i]="${COMPREPLY[i]#"$colon"}"
+        done
+    fi
+}
+
+complete -F _agm agm
+`
+
+const zshCompletion = `#compdef agm
+# zsh completion for agm
+# Load with: source <(agm completion zsh)
+
+_agm() {
+    local out
+    out="$(agm __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"
+    if [[ "$out" == ":files" ]]; then
+        _files
+        return
+    fi
+    local -a candidates
+    candidates=(${(f)out})
+    compadd -Q -a candidates
+}
+
+if [[ "$funcstack[1]" == "_agm" ]]; then
+    _agm "$@"
+else
+    compdef _agm agm
+fi
+`
+
+const fishCompletion = `# fish completion for agm
+# Load with: agm completion fish | source
+
+function __agm_complete
+    set -l args (commandline -opc)[2..-1] (commandline -ct)
+    set -l out (agm __complete $args 2>/dev/null)
+    if test "$out" = ":files"
+        __fish_complete_path (commandline -ct)
+    else
+        printf '%s\n' $out
+    end
+end
+
+complete -c agm -f -a '(__agm_complete)'
+`
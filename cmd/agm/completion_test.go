@@ -0,0 +1,34 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{[]string{"li"}, []string{"list", "link"}},
+		{[]string{"help", "unl"}, []string{"unlink"}},
+		{[]string{"link", "--all"}, []string{"--all-tools"}},
+		{[]string{"link", "--tool", "cl"}, []string{"claude"}},
+		{[]string{"unlink", "--tool=cu"}, []string{"--tool=cursor"}},
+		{[]string{"sync", "-o", ""}, []string{"text", "json"}},
+		{[]string{"link", "--project", ""}, []string{completeFiles}},
+		{[]string{"add", "./"}, []string{completeFiles}},
+		{[]string{"completion", "z"}, []string{"zsh"}},
+		{[]string{"version", ""}, nil},
+		{[]string{"bogus", ""}, nil},
+	}
+	for _, tt := range tests {
+		if got := complete(tt.words); !slices.Equal(got, tt.want) {
+			t.Errorf("complete(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
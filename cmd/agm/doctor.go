@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runDoctor(c *command, args []string) error {
+	fs := c.flagSet()
+	fix := fs.Bool("fix", false, "Repair the problems that can be fixed safely")
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	report, err := commands.Doctor(*fix)
+	if report == nil {
+		return err
+	}
+	if asJSON {
+		if werr := output.WriteJSON(os.Stdout, "doctor", report); werr != nil && err == nil {
+			err = werr
+		}
+		return err
+	}
+	report.Print()
+	return err
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runGC(c *command, args []string) error {
+	fs := c.flagSet()
+	var opts commands.GCOptions
+	fs.BoolVar(&opts.Git, "git", false, "Also run git gc in every skill clone and the registry clone")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		plan, err := commands.PlanGC(opts)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.GC(opts)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "gc", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runHooks(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	format := addOutputFlag(fs)
+	actions, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(actions) != 1 {
+		return &usageError{cmd: c.name, msg: "expected one of: install, uninstall"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	var result *commands.HooksResult
+	switch actions[0] {
+	case "install":
+		result, err = commands.InstallHooks(*projectDir)
+	case "uninstall":
+		result, err = commands.UninstallHooks(*projectDir)
+	default:
+		return &usageError{cmd: c.name, msg: "unknown action " + actions[0] + " (expected install or uninstall)"}
+	}
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "hooks", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runIgnore(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	format := addOutputFlag(fs)
+	modes, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(modes) > 1 {
+		return &usageError{cmd: c.name, msg: "expected at most one of: gitignore, exclude, off"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	var mode string
+	if len(modes) == 1 {
+		mode = modes[0]
+	}
+	result, err := commands.SetIgnoreMode(*projectDir, mode)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "ignore", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runInfo(c *command, args []string) error {
+	fs := c.flagSet()
+	var projects stringList
+	fs.Var(&projects, "project", "Look for links in project `dir` (repeatable, default: current directory)")
+	format := addOutputFlag(fs)
+	ids, err := parseSkillArgs(c, fs, args)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 1 {
+		return &usageError{cmd: c.name, msg: "expected a single <skill-id>"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	info, err := commands.Info(ids[0], projects)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.WriteJSON(os.Stdout, "skill", info)
+	}
+	info.Print()
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runInstall(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	frozen := fs.Bool("frozen", false, "Install exactly the versions in agm.lock; this moves the skills in every project linked to them")
+	prune := fs.Bool("prune", false, "Remove links to agm's skills that .agm.json does not declare")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	opts := commands.InstallOptions{Dir: *projectDir, Frozen: *frozen, Prune: *prune}
+	if *dryRun {
+		plan, err := commands.PlanInstall(opts)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.Install(opts)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "install", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+)
+
+// linkReport is the JSON document emitted by agm link and agm unlink.
+type linkReport struct {
+	Results []commands.LinkResult `json:"results"`
+}
+
+func runLink(c *command, args []string) error {
+	return runLinkOp(c, args, commands.Link, commands.PlanLink)
+}
+
+func runUnlink(c *command, args []string) error {
+	return runLinkOp(c, args, commands.Unlink, commands.PlanUnlink)
+}
+
+func runLinkOp(c *command, args []string,
+	op func([]string, []project.Info) ([]commands.LinkResult, error),
+	planOp func([]string, []project.Info) (*commands.Plan, error),
+) error {
+	fs := c.flagSet()
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	ids, projects, err := parseLinkArgs(c, fs, args)
+	if err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		plan, err := planOp(ids, projects)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	results, err := op(ids, projects)
+	if asJSON && results != nil {
+		if werr := output.WriteJSON(os.Stdout, c.name, linkReport{Results: results}); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// parseLinkArgs parses the tool targeting flags, resolves the skill arguments
+// and selects the project tools to operate on.
+func parseLinkArgs(c *command, fs *flag.FlagSet, args []string) ([]string, []project.Info, error) {
+	var tools stringList
+	fs.Var(&tools, "tool", "Target AI tool `type`, e.g. claude or cursor (repeatable)")
+	allTools := fs.Bool("all-tools", false, "Target every AI tool detected in the project")
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+
+	ids, err := parseSkillArgs(c, fs, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if *allTools && len(tools) > 0 {
+		return nil, nil, &usageError{cmd: c.name, msg: "--tool and --all-tools are mutually exclusive"}
+	}
+
+	projects, err := commands.SelectProjects(*projectDir, tools, *allTools)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, projects, nil
+}
@@ -1,80 +1,231 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
 
 	"github.com/ArdentaCorp/agent-management/internal/commands"
 	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
 	"github.com/ArdentaCorp/agent-management/internal/tui"
 	"github.com/charmbracelet/huh"
 )
 
 const version = "1.0.2"
 
+// subcommands lists the scriptable subcommands in help order.
+var subcommands = []*command{
+	{name: "list", aliases: []string{"ls"}, summary: "List installed skills", run: runList, complete: completeNone},
+	{name: "info", args: "<skill-id>", summary: "Show details of an installed skill", run: runInfo, complete: completeSkillIDs},
+	{name: "add", args: "<url|path>...", summary: "Import skills from a GitHub URL or a local folder", run: runAdd},
+	{name: "link", args: "<skill-id>...", summary: "Link skills into a project's AI tool skill directories", run: runLink, complete: completeSkillIDs},
+	{name: "unlink", args: "<skill-id>...", summary: "Remove skill links from a project's AI tool skill directories", run: runUnlink, complete: completeSkillIDs},
+	{name: "status", summary: "Show how the project's tool skill directories differ from agm", run: runStatus, complete: completeNone},
+	{name: "verify", summary: "Check the project's skill links for CI and fail on any drift", run: runVerify, complete: completeNone},
+	{name: "install", summary: "Import and link the skills listed in the project's .agm.json", run: runInstall, complete: completeNone},
+	{name: "hooks", args: "<install|uninstall>", summary: "Install git hooks that re-apply the project's skill links on checkout and merge", run: runHooks, complete: completeHookActions},
+	{name: "ignore", args: "[gitignore|exclude|off]", summary: "Keep the links agm creates in the project out of git", run: runIgnore, complete: completeIgnoreModes},
+	{name: "vendor", args: "[<skill-id>...]", summary: "Replace the project's skill links with copies that work without agm", run: runVendor, complete: completeSkillIDs},
+	{name: "relink", args: "[absolute|relative|home]", summary: "Rewrite the project's skill links to use a link strategy", run: runRelink, complete: completeLinkStrategies},
+	{name: "outdated", summary: "Show which GitHub and registry skills have updates", run: runOutdated, complete: completeNone},
+	{name: "update", args: "<skill-id>...|--all", summary: "Pull the latest changes for GitHub skills", run: runUpdate, complete: completeSkillIDs},
+	{name: "remove", aliases: []string{"rm"}, args: "<skill-id>...", summary: "Delete skills from the repository", run: runRemove, complete: completeSkillIDs},
+	{name: "sync", summary: "Sync skills from the registry", run: runSync, complete: completeNone},
+	{name: "history", summary: "List recorded operations that can be undone", run: runHistory, complete: completeNone},
+	{name: "undo", summary: "Reverse the most recent operation", run: runUndo, complete: completeNone},
+	{name: "doctor", summary: "Check the installation for problems and optionally fix them", run: runDoctor, complete: completeNone},
+	{name: "gc", summary: "Delete orphaned skill directories, leaked temp clones and stale backups", run: runGC, complete: completeNone},
+	{name: "config", summary: "Show configuration", run: runConfig, complete: completeNone},
+	{name: "completion", args: "<bash|zsh|fish>", summary: "Print a shell completion script", run: runCompletion, complete: completeShells},
+	{name: "version", summary: "Show version number", run: runVersion, complete: completeNone},
+}
+
 func main() {
-	args := os.Args[1:]
-
-	if len(args) > 0 {
-		for _, arg := range args {
-			switch arg {
-			case "--version", "-v":
-				fmt.Printf("agm version %s\n", version)
-				return
-			case "--config":
-				showConfig()
-				return
-			case "--sync":
-				fmt.Print(tui.RenderBanner(version))
-				commands.SyncSkills(false)
-				return
-			case "--help", "-h":
-				printHelp()
-				return
-			default:
-				fmt.Fprintln(os.Stderr, tui.RenderError("Unknown argument: "+arg))
-				printHelp()
-				os.Exit(1)
+	os.Exit(run(os.Args[1:]))
+}
+
+// run dispatches the command line and returns the process exit code.
+func run(args []string) int {
+	setVerbose(traceFromEnv())
+	if len(args) > 0 && args[0] == "--verbose" {
+		setVerbose(true)
+		args = args[1:]
+	}
+	timeout, err := gitTimeout()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, tui.RenderError(err.Error()))
+		return exitConfig
+	}
+	git.SetTimeout(timeout)
+
+	// The first interrupt cancels running git commands so staged clones are
+	// cleaned up; a second one kills agm as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	git.SetContext(ctx)
+	showProgress()
+
+	if len(args) == 0 {
+		mainMenu()
+		return exitOK
+	}
+
+	// Flags kept for compatibility with agm 1.0.
+	switch args[0] {
+	case "--version", "-v":
+		args = []string{"version"}
+	case "--config":
+		args = []string{"config"}
+	case "--sync":
+		fmt.Print(tui.RenderBanner(version))
+		args = append([]string{"sync"}, args[1:]...)
+	case completeCommand:
+		return runComplete(args[1:])
+	case "--help", "-h", "help":
+		if len(args) > 1 {
+			if c := findCommand(args[1]); c != nil {
+				args = []string{c.name, "--help"}
+				break
 			}
 		}
-		return
+		printHelp()
+		return exitOK
+	}
+
+	c := findCommand(args[0])
+	if c == nil {
+		fmt.Fprintln(os.Stderr, tui.RenderError("Unknown command: "+args[0]))
+		fmt.Fprintln(os.Stderr, tui.MutedText.Render("Run 'agm help' for usage."))
+		return exitUsage
+	}
+
+	err = c.run(c, args[1:])
+	if err == nil || errors.Is(err, errHelp) {
+		return exitOK
+	}
+	if errors.Is(err, context.Canceled) {
+		fmt.Fprintln(os.Stderr, tui.RenderError("Interrupted"))
+		return exitInterrupted
+	}
+
+	// "Updates available" is a result, already shown in the report.
+	if commands.KindOf(err) != commands.KindOutdated {
+		fmt.Fprintln(os.Stderr, tui.RenderError(err.Error()))
 	}
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintln(os.Stderr, tui.MutedText.Render("Run 'agm help "+usageErr.cmd+"' for usage."))
+		return exitUsage
+	}
+	return exitCode(err)
+}
+
+// Process exit codes. Keep in sync with the Exit codes section of README.md.
+const (
+	exitOK         = 0
+	exitFailure    = 1
+	exitUsage      = 2
+	exitConfig     = 3
+	exitGit        = 4
+	exitNetwork    = 5
+	exitValidation = 6
+	exitConflict   = 7
+	exitNotFound   = 8
+	exitOutdated   = 9
+	exitDrift      = 10
+
+	exitInterrupted = 130 // 128 + SIGINT, as shells report it
+)
 
-	mainMenu()
+var exitCodes = map[commands.ErrorKind]int{
+	commands.KindConfig:     exitConfig,
+	commands.KindGit:        exitGit,
+	commands.KindNetwork:    exitNetwork,
+	commands.KindValidation: exitValidation,
+	commands.KindConflict:   exitConflict,
+	commands.KindNotFound:   exitNotFound,
+	commands.KindOutdated:   exitOutdated,
+	commands.KindDrift:      exitDrift,
+}
+
+// exitCode maps a command error to its process exit code.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if code, ok := exitCodes[commands.KindOf(err)]; ok {
+		return code
+	}
+	return exitFailure
+}
+
+func findCommand(name string) *command {
+	for _, c := range subcommands {
+		if c.name == name || slices.Contains(c.aliases, name) {
+			return c
+		}
+	}
+	return nil
 }
 
 func printHelp() {
 	fmt.Println(tui.RenderBanner(version))
-	fmt.Println("Usage: agm [options]")
+	fmt.Println("Usage: agm <command> [flags]")
 	fmt.Println()
 	fmt.Println("  A CLI tool to manage and synchronize AI coding agent skills")
 	fmt.Println()
-	fmt.Println("Options:")
-	fmt.Println("  --version, -v  Show version number")
-	fmt.Println("  --config       Show configuration")
-	fmt.Println("  --sync         Sync skills from registry (non-interactive)")
-	fmt.Println("  --help, -h     Show this help message")
+	fmt.Println("Commands:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, c := range subcommands {
+		fmt.Fprintf(w, "  %s\t%s\n", c.name, c.summary)
+	}
+	w.Flush()
 	fmt.Println()
+	fmt.Println("Run 'agm help <command>' for details on a command.")
 	fmt.Println("Run without arguments for interactive mode.")
 }
 
-func showConfig() {
+func runConfig(c *command, args []string) error {
+	fs := c.flagSet()
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+
 	cm, err := config.NewManager()
 	if err != nil {
-		fmt.Fprintln(os.Stderr, tui.RenderError("Failed to initialize config: "+err.Error()))
-		return
+		return &commands.Error{Kind: commands.KindConfig, Msg: "failed to initialize config", Err: err}
 	}
 	fmt.Println(tui.RenderBanner(version))
 	fmt.Println(tui.RenderInfo("Config directory: " + cm.GetHomeDir()))
 
 	cfg, err := cm.LoadConfig()
 	if err != nil {
-		return
+		return &commands.Error{Kind: commands.KindConfig, Msg: "failed to read config", Err: err}
 	}
 
 	data, _ := json.MarshalIndent(cfg, "", "  ")
 	fmt.Printf("\n%s\n", string(data))
+	return nil
+}
+
+func runVersion(c *command, args []string) error {
+	fs := c.flagSet()
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	fmt.Printf("agm version %s\n", version)
+	return nil
 }
 
 func mainMenu() {
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// skillList is the JSON document emitted by agm list.
+type skillList struct {
+	Skills []commands.SkillEntry `json:"skills"`
+}
+
+func runList(c *command, args []string) error {
+	fs := c.flagSet()
+	quiet := fs.Bool("q", false, "Print skill IDs only")
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	entries, err := commands.ListSkills(project.NewDetector("").DetectAll())
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return output.WriteJSON(os.Stdout, "skills", skillList{Skills: entries})
+	}
+
+	for _, s := range entries {
+		if *quiet {
+			fmt.Println(s.ID)
+			continue
+		}
+		detail := s.Type
+		if s.Commit != "" {
+			detail += ", " + s.Commit[:min(7, len(s.Commit))]
+		}
+		line := s.ID + " " + tui.MutedText.Render("("+detail+")")
+		if len(s.LinkedTools) > 0 {
+			line += " " + tui.SuccessText.Render("→ "+strings.Join(s.LinkedTools, ", "))
+		}
+		fmt.Println(line)
+	}
+	if len(entries) == 0 && !*quiet {
+		fmt.Println(tui.MutedText.Render("No skills installed."))
+	}
+	return nil
+}
+
+func runUpdate(c *command, args []string) error {
+	fs := c.flagSet()
+	all := fs.Bool("all", false, "Update every GitHub skill")
+	jobs := fs.Int("jobs", commands.DefaultJobs, "Fetch and pull at most `n` skills at once")
+	fs.IntVar(jobs, "j", commands.DefaultJobs, "Shorthand for --jobs")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	names, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	switch {
+	case *all && len(names) > 0:
+		return &usageError{cmd: c.name, msg: "pass either <skill-id>... or --all, not both"}
+	case *all:
+		if ids, err = commands.GitHubSkillIDs(); err != nil {
+			return err
+		}
+	case len(names) == 0:
+		return &usageError{cmd: c.name, msg: "missing <skill-id> or --all"}
+	default:
+		if ids, err = commands.ResolveSkillIDs(names); err != nil {
+			return err
+		}
+	}
+	if *jobs < 1 {
+		return &usageError{cmd: c.name, msg: "--jobs must be at least 1"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		plan, err := commands.PlanUpdate(ids, *jobs)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.Update(ids, *jobs)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "update", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+func runRemove(c *command, args []string) error {
+	fs := c.flagSet()
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	ids, err := parseSkillArgs(c, fs, args)
+	if err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		plan, err := commands.PlanRemove(ids, project.NewDetector("").DetectAll())
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+	if asJSON {
+		return &usageError{cmd: c.name, msg: "--output json requires --dry-run"}
+	}
+	return commands.Remove(ids)
+}
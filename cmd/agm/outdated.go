@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runOutdated(c *command, args []string) error {
+	fs := c.flagSet()
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	report, err := commands.Outdated()
+	if report == nil {
+		return err
+	}
+	if asJSON {
+		if werr := output.WriteJSON(os.Stdout, "outdated", report); werr != nil && err == nil {
+			err = werr
+		}
+		return err
+	}
+	report.Print()
+	return err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runRelink(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	strategies, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(strategies) > 1 {
+		return &usageError{cmd: c.name, msg: "expected at most one of: absolute, relative, home"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	var strategy string
+	if len(strategies) == 1 {
+		strategy = strategies[0]
+	}
+	if *dryRun {
+		plan, err := commands.PlanRelink(*projectDir, strategy)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.Relink(*projectDir, strategy)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "relink", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runStatus(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	report, err := commands.Status(*projectDir)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.WriteJSON(os.Stdout, "status", report)
+	}
+	report.Print()
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runSync(c *command, args []string) error {
+	fs := c.flagSet()
+	registry := fs.String("registry", "", "Set the registry URL before syncing")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	if *dryRun {
+		plan, err := commands.PlanSync(*registry)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.Sync(*registry)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "sync", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
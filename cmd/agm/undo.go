@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// historyReport is the JSON document emitted by agm history.
+type historyReport struct {
+	Entries []commands.HistoryEntry `json:"entries"`
+}
+
+func runHistory(c *command, args []string) error {
+	fs := c.flagSet()
+	limit := fs.Int("n", 20, "Show at most `count` entries (0 for all)")
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	history, err := commands.History()
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(history) > *limit {
+		history = history[:*limit]
+	}
+
+	if asJSON {
+		return output.WriteJSON(os.Stdout, "history", historyReport{Entries: history})
+	}
+	if len(history) == 0 {
+		fmt.Println(tui.MutedText.Render("No recorded operations."))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, e := range history {
+		detail := fmt.Sprintf("%d change(s)", len(e.Changes))
+		if e.Undoes != 0 {
+			detail = fmt.Sprintf("reverted #%d", e.Undoes)
+		}
+		if e.Undone {
+			detail += " " + tui.MutedText.Render("(undone)")
+		}
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\n", e.ID, e.Time.Local().Format("2006-01-02 15:04"), e.Op, detail)
+	}
+	return w.Flush()
+}
+
+func runUndo(c *command, args []string) error {
+	fs := c.flagSet()
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	entry, err := commands.Undo()
+	if err != nil {
+		return err
+	}
+	fmt.Println(tui.RenderSuccess(fmt.Sprintf("Undid #%d %s", entry.ID, entry.Op)))
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runVendor(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	update := fs.Bool("update", false, "Refresh vendored copies from agm's repo instead of vendoring links")
+	force := fs.Bool("force", false, "With --update, overwrite copies that were edited")
+	format := addOutputFlag(fs)
+	dryRun := addDryRunFlag(fs)
+	names, err := c.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if *force && !*update {
+		return &usageError{cmd: c.name, msg: "--force only applies with --update"}
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	opts := commands.VendorOptions{Dir: *projectDir, Update: *update, Force: *force}
+	if len(names) > 0 {
+		if opts.IDs, err = commands.ResolveSkillIDs(names); err != nil {
+			return err
+		}
+	}
+	if *dryRun {
+		plan, err := commands.PlanVendor(opts)
+		if err != nil {
+			return err
+		}
+		return writePlan(plan, asJSON)
+	}
+
+	result, err := commands.Vendor(opts)
+	if asJSON && result != nil {
+		if werr := output.WriteJSON(os.Stdout, "vendor", result); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/commands"
+	"github.com/ArdentaCorp/agent-management/internal/output"
+)
+
+func runVerify(c *command, args []string) error {
+	fs := c.flagSet()
+	projectDir := fs.String("project", "", "Project `dir`ectory (default: current directory)")
+	format := addOutputFlag(fs)
+	if err := c.parseNone(fs, args); err != nil {
+		return err
+	}
+	asJSON, err := c.jsonOutput(*format)
+	if err != nil {
+		return err
+	}
+
+	report, err := commands.Verify(*projectDir)
+	if report == nil {
+		return err
+	}
+	if asJSON {
+		if werr := output.WriteJSON(os.Stdout, "verify", report); werr != nil && err == nil {
+			err = werr
+		}
+		return err
+	}
+	report.Print()
+	return err
+}
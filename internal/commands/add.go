@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -10,18 +11,59 @@ import (
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
 	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
 	"github.com/ArdentaCorp/agent-management/internal/project"
 	"github.com/ArdentaCorp/agent-management/internal/skills"
 	"github.com/ArdentaCorp/agent-management/internal/tui"
 	"github.com/charmbracelet/huh"
 )
 
+// errCancelled is returned when the user backs out of an interactive prompt.
+var errCancelled = errors.New("cancelled")
+
+// Candidate is a skill found in a source that may be imported.
+type Candidate struct {
+	Name      string
+	ID        string
+	Installed bool
+}
+
+// AddOptions controls how Add treats folders of skills and skills that are
+// already installed.
+type AddOptions struct {
+	// Select picks which candidates to import from a folder of skills and
+	// returns their names. When nil, every candidate is imported.
+	Select func(found []Candidate) ([]string, error)
+	// Overwrite reports whether an installed skill should be replaced.
+	// When nil, installed skills are skipped.
+	Overwrite func(id string) bool
+}
+
+func (o AddOptions) selectSkills(found []Candidate) ([]string, error) {
+	if o.Select == nil {
+		names := make([]string, 0, len(found))
+		for _, c := range found {
+			names = append(names, c.Name)
+		}
+		return names, nil
+	}
+	return o.Select(found)
+}
+
+func (o AddOptions) overwrite(id string) bool {
+	if o.Overwrite == nil {
+		fmt.Fprintln(out, tui.MutedText.Render("Skipped "+id+" (already installed)"))
+		return false
+	}
+	return o.Overwrite(id)
+}
+
 // AddSkills is the top-level "Add skills" flow.
 // After adding, it offers to link to a detected project immediately.
 func AddSkills() {
 	cm, err := config.NewManager()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
+		fmt.Fprintln(out, tui.RenderError("Failed to initialize config: "+err.Error()))
 		return
 	}
 	registryURL := cm.GetRegistry()
@@ -98,11 +140,19 @@ func offerLinkAfterAdd(addedIDs []string) {
 		return
 	}
 
+	cm, err := openConfig()
+	if err != nil {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+		return
+	}
+	op := beginJournal(cm, "link")
+	defer finishJournal(op)
+
 	// If only one tool detected, link directly
 	if len(projects) == 1 {
 		p := projects[0]
 		for _, id := range addedIDs {
-			linkSkillToProject(id, &p)
+			linkOrReport(op, id, &p)
 		}
 		return
 	}
@@ -128,9 +178,9 @@ func offerLinkAfterAdd(addedIDs []string) {
 	if idx == -1 {
 		// Link to all detected tools
 		for _, p := range projects {
-			fmt.Println(tui.RenderInfo("Linking to " + p.Type + "..."))
+			fmt.Fprintln(out, tui.RenderInfo("Linking to "+p.Type+"..."))
 			for _, id := range addedIDs {
-				linkSkillToProject(id, &p)
+				linkOrReport(op, id, &p)
 			}
 		}
 	} else {
@@ -139,21 +189,29 @@ func offerLinkAfterAdd(addedIDs []string) {
 		}
 		p := projects[idx]
 		for _, id := range addedIDs {
-			linkSkillToProject(id, &p)
+			linkOrReport(op, id, &p)
 		}
 	}
 }
 
-// addGitHubSkill adds a skill from a GitHub URL. Returns added skill IDs.
-func addGitHubSkill() []string {
-	cm, err := config.NewManager()
-	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return nil
+// Add imports skills from a GitHub URL or a local folder and returns the added IDs.
+func Add(source string, opts AddOptions) ([]string, error) {
+	source = strings.TrimSpace(source)
+	if isRemoteSource(source) {
+		return AddGitHub(source, opts)
 	}
-	registry := skills.NewRegistry(cm)
-	gitMgr := git.NewManager()
+	return AddFolder(source, opts)
+}
 
+// isRemoteSource reports whether source looks like a URL rather than a path.
+func isRemoteSource(source string) bool {
+	return strings.HasPrefix(source, "https://") ||
+		strings.HasPrefix(source, "http://") ||
+		strings.HasPrefix(source, "github.com/")
+}
+
+// addGitHubSkill prompts for a GitHub URL and adds its skills. Returns added skill IDs.
+func addGitHubSkill() []string {
 	var repoURL string
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -164,18 +222,36 @@ func addGitHubSkill() []string {
 				Value(&repoURL),
 		),
 	)
-	if err := form.Run(); err != nil || repoURL == "" {
+	if err := form.Run(); err != nil || strings.TrimSpace(repoURL) == "" {
 		return nil
 	}
 
+	addedIDs, err := AddGitHub(repoURL, interactiveAddOptions())
+	if err != nil && !errors.Is(err, errCancelled) {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+	}
+	return addedIDs
+}
+
+// AddGitHub adds the skill or folder of skills at a GitHub URL. Returns added skill IDs.
+func AddGitHub(repoURL string, opts AddOptions) ([]string, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+	gitMgr := git.NewManager()
+
 	repoURL = strings.TrimSpace(repoURL)
+	if strings.HasPrefix(repoURL, "github.com/") {
+		repoURL = "https://" + repoURL
+	}
 	if repoURL == "" {
-		return nil
+		return nil, newError(KindValidation, "no GitHub URL given")
 	}
 
 	if err := gitMgr.CheckGitVersion(); err != nil {
-		fmt.Println(tui.RenderError(err.Error()))
-		return nil
+		return nil, classify(KindGit, err)
 	}
 
 	gitInfo := gitMgr.NormalizeURL(repoURL)
@@ -183,8 +259,7 @@ func addGitHubSkill() []string {
 	re := regexp.MustCompile(`github\.com/([^/]+/[^/]+?)(\.git)?$`)
 	matches := re.FindStringSubmatch(gitInfo.URL)
 	if matches == nil {
-		fmt.Println(tui.RenderError("Only GitHub URLs are supported."))
-		return nil
+		return nil, newError(KindValidation, "only GitHub URLs are supported")
 	}
 	userRepo := strings.TrimSuffix(matches[1], ".git")
 
@@ -193,53 +268,52 @@ func addGitHubSkill() []string {
 		branch = gitMgr.GetDefaultBranch(userRepo)
 	}
 
-	fmt.Println(tui.RenderInfo("Checking for SKILL.md..."))
+	fmt.Fprintln(out, tui.RenderInfo("Checking for SKILL.md..."))
 	isSingleSkill := gitMgr.CheckRemoteSkillMd(userRepo, branch, gitInfo.Path)
 
+	op := beginJournal(cm, "add")
+	defer finishJournal(op)
+
 	if isSingleSkill {
-		return addSingleGitHubSkill(cm, registry, gitMgr, gitInfo, userRepo, branch)
+		return addSingleGitHubSkill(op, cm, registry, gitMgr, gitInfo, userRepo, branch, opts)
 	}
 
 	// No SKILL.md at root — might be a folder of skills. Clone and scan.
-	return addGitHubSkillsFolder(cm, registry, gitMgr, gitInfo, userRepo, branch)
+	return addGitHubSkillsFolder(op, cm, registry, gitMgr, gitInfo, userRepo, branch, opts)
 }
 
 // addSingleGitHubSkill handles a GitHub URL pointing to a single skill (has SKILL.md).
-func addSingleGitHubSkill(cm *config.Manager, registry *skills.Registry, gitMgr *git.Manager, gitInfo git.URLInfo, userRepo, branch string) []string {
+func addSingleGitHubSkill(op *journal.Op, cm *config.Manager, registry *skills.Registry, gitMgr *git.Manager, gitInfo git.URLInfo, userRepo, branch string, opts AddOptions) ([]string, error) {
 	id := "github:" + userRepo
 	if gitInfo.Path != "" {
 		id += "/" + gitInfo.Path
 	}
 
-	if existing := registry.GetSkill(id); existing != nil {
-		var overwrite bool
-		if err := huh.NewForm(huh.NewGroup(
-			huh.NewConfirm().
-				Title(fmt.Sprintf("%s already exists. Overwrite?", id)).
-				Value(&overwrite),
-		)).Run(); err != nil {
-			return nil
+	existing := registry.GetSkill(id)
+	if existing != nil {
+		if !opts.overwrite(id) {
+			return nil, nil
 		}
-		if !overwrite {
-			return nil
+		if err := op.SkillReplaced(*existing, cm.GetRepoPath(id)); err != nil {
+			return nil, err
 		}
-		os.RemoveAll(cm.GetRepoPath(id))
 	}
 
 	destPath := cm.GetRepoPath(id)
 	os.MkdirAll(filepath.Dir(destPath), 0755)
 
-	fmt.Println(tui.RenderInfo("Cloning " + id + "..."))
+	fmt.Fprintln(out, tui.RenderInfo("Cloning "+id+"..."))
 
 	var err error
+	cloneMgr, cloned := trackGit(gitMgr, "clone", id)
 	if gitInfo.Path != "" {
-		err = gitMgr.CloneSparseQuiet(gitInfo.URL, destPath, gitInfo.Path, branch)
+		err = cloneMgr.CloneSparseQuiet(gitInfo.URL, destPath, gitInfo.Path, branch)
 	} else {
-		err = gitMgr.CloneFullQuiet(gitInfo.URL, destPath)
+		err = cloneMgr.CloneFullQuiet(gitInfo.URL, destPath)
 	}
+	cloned()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to clone: " + err.Error()))
-		return nil
+		return nil, gitError(err, "failed to clone")
 	}
 
 	subPath := "."
@@ -249,28 +323,32 @@ func addSingleGitHubSkill(cm *config.Manager, registry *skills.Registry, gitMgr
 	commitID, _ := gitMgr.GetLocalPathCommitID(destPath, subPath)
 
 	registry.AddSkill(id, "github", commitID, gitInfo.Path)
-	fmt.Println(tui.RenderSuccess("Added " + id))
-	return []string{id}
+	if existing == nil {
+		op.SkillAdded(id)
+	}
+	fmt.Fprintln(out, tui.RenderSuccess("Added "+id))
+	return []string{id}, nil
 }
 
 // addGitHubSkillsFolder handles a GitHub URL pointing to a folder of skills (no SKILL.md at root).
-// Clones the path, scans for subdirectories with SKILL.md, and lets the user pick.
-func addGitHubSkillsFolder(cm *config.Manager, registry *skills.Registry, gitMgr *git.Manager, gitInfo git.URLInfo, userRepo, branch string) []string {
-	fmt.Println(tui.RenderInfo("No SKILL.md at root — scanning for skills inside..."))
+// Clones the path, scans for subdirectories with SKILL.md, and lets the caller pick.
+func addGitHubSkillsFolder(op *journal.Op, cm *config.Manager, registry *skills.Registry, gitMgr *git.Manager, gitInfo git.URLInfo, userRepo, branch string, opts AddOptions) ([]string, error) {
+	fmt.Fprintln(out, tui.RenderInfo("No SKILL.md at root — scanning for skills inside..."))
 
 	// Clone to a temp location to scan
 	tmpDir := filepath.Join(os.TempDir(), fmt.Sprintf("agm-scan-%d", os.Getpid()))
 	defer os.RemoveAll(tmpDir)
 
 	var err error
+	cloneMgr, cloned := trackGit(gitMgr, "clone", gitInfo.URL)
 	if gitInfo.Path != "" {
-		err = gitMgr.CloneSparseQuiet(gitInfo.URL, tmpDir, gitInfo.Path, branch)
+		err = cloneMgr.CloneSparseQuiet(gitInfo.URL, tmpDir, gitInfo.Path, branch)
 	} else {
-		err = gitMgr.CloneFullQuiet(gitInfo.URL, tmpDir)
+		err = cloneMgr.CloneFullQuiet(gitInfo.URL, tmpDir)
 	}
+	cloned()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to clone: " + err.Error()))
-		return nil
+		return nil, gitError(err, "failed to clone")
 	}
 
 	// Scan for skills
@@ -279,66 +357,45 @@ func addGitHubSkillsFolder(cm *config.Manager, registry *skills.Registry, gitMgr
 		scanRoot = filepath.Join(tmpDir, gitInfo.Path)
 	}
 
-	type skillEntry struct {
-		name string
+	if _, err := os.ReadDir(scanRoot); err != nil {
+		return nil, fmt.Errorf("error reading directory: %w", err)
 	}
-	var found []skillEntry
 
-	entries, err := os.ReadDir(scanRoot)
-	if err != nil {
-		fmt.Println(tui.RenderError("Error reading directory: " + err.Error()))
-		return nil
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
-		if _, err := os.Stat(filepath.Join(scanRoot, entry.Name(), "SKILL.md")); err == nil {
-			found = append(found, skillEntry{name: entry.Name()})
+	skillID := func(name string) string {
+		id := "github:" + userRepo
+		if gitInfo.Path != "" {
+			id += "/" + gitInfo.Path
 		}
+		return id + "/" + name
 	}
 
-	if len(found) == 0 {
-		fmt.Println(tui.RenderError("No skills found (no subdirectories with SKILL.md)."))
-		return nil
+	var found []Candidate
+	for _, dir := range scanForSkills(scanRoot) {
+		name := filepath.Base(dir)
+		id := skillID(name)
+		found = append(found, Candidate{Name: name, ID: id, Installed: registry.GetSkill(id) != nil})
 	}
 
-	// Let user pick
-	var opts []huh.Option[string]
-	for _, s := range found {
-		id := "github:" + userRepo
-		if gitInfo.Path != "" {
-			id += "/" + gitInfo.Path
-		}
-		id += "/" + s.name
-		label := s.name
-		if existing := registry.GetSkill(id); existing != nil {
-			label += " " + tui.MutedText.Render("(installed)")
-		}
-		opts = append(opts, huh.NewOption(label, s.name))
+	if len(found) == 0 {
+		return nil, newError(KindNotFound, "no skills found (no subdirectories with SKILL.md)")
 	}
 
-	var selected []string
-	if err := huh.NewForm(huh.NewGroup(
-		huh.NewMultiSelect[string]().
-			Title(fmt.Sprintf("Found %d skills — select which to add", len(found))).
-			Options(opts...).
-			Value(&selected),
-	)).Run(); err != nil {
-		return nil
+	selected, err := opts.selectSkills(found)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(selected) == 0 {
-		fmt.Println(tui.MutedText.Render("No skills selected."))
-		return nil
+		fmt.Fprintln(out, tui.MutedText.Render("No skills selected."))
+		return nil, nil
 	}
 
 	var addedIDs []string
+	var errs []error
 	for _, selName := range selected {
-		var match *skillEntry
+		var match *Candidate
 		for i := range found {
-			if found[i].name == selName {
+			if found[i].Name == selName {
 				match = &found[i]
 				break
 			}
@@ -347,61 +404,57 @@ func addGitHubSkillsFolder(cm *config.Manager, registry *skills.Registry, gitMgr
 			continue
 		}
 
-		id := "github:" + userRepo
-		if gitInfo.Path != "" {
-			id += "/" + gitInfo.Path
-		}
-		id += "/" + match.name
+		id := match.ID
 
-		skillSubPath := match.name
+		skillSubPath := match.Name
 		if gitInfo.Path != "" {
-			skillSubPath = gitInfo.Path + "/" + match.name
+			skillSubPath = gitInfo.Path + "/" + match.Name
 		}
 
-		if existing := registry.GetSkill(id); existing != nil {
-			var overwrite bool
-			if err := huh.NewForm(huh.NewGroup(
-				huh.NewConfirm().
-					Title(fmt.Sprintf("%s already exists. Overwrite?", id)).
-					Value(&overwrite),
-			)).Run(); err != nil {
+		existing := registry.GetSkill(id)
+		if existing != nil {
+			if !opts.overwrite(id) {
 				continue
 			}
-			if !overwrite {
+			if err := op.SkillReplaced(*existing, cm.GetRepoPath(id)); err != nil {
+				fmt.Fprintln(out, tui.RenderError("Failed: "+match.Name+": "+err.Error()))
+				errs = append(errs, err)
 				continue
 			}
-			os.RemoveAll(cm.GetRepoPath(id))
 		}
 
 		destPath := cm.GetRepoPath(id)
 		os.MkdirAll(filepath.Dir(destPath), 0755)
-		fmt.Println(tui.RenderInfo("Cloning " + match.name + "..."))
-		if err := gitMgr.CloneSparseQuiet(gitInfo.URL, destPath, skillSubPath, branch); err != nil {
-			fmt.Println(tui.RenderError("Failed to clone " + match.name + ": " + err.Error()))
+		fmt.Fprintln(out, tui.RenderInfo("Cloning "+match.Name+"..."))
+		cloneMgr, cloned := trackGit(gitMgr, "clone", id)
+		err := cloneMgr.CloneSparseQuiet(gitInfo.URL, destPath, skillSubPath, branch)
+		cloned()
+		if err != nil {
+			fmt.Fprintln(out, tui.RenderError("Failed to clone "+match.Name+": "+err.Error()))
+			errs = append(errs, gitError(err, "failed to clone %s", match.Name))
 			continue
 		}
 
 		commitID, _ := gitMgr.GetLocalPathCommitID(destPath, skillSubPath)
 		registry.AddSkill(id, "github", commitID, skillSubPath)
+		if existing == nil {
+			op.SkillAdded(id)
+		}
 		addedIDs = append(addedIDs, id)
-		fmt.Println(tui.RenderSuccess("Added " + id))
+		fmt.Fprintln(out, tui.RenderSuccess("Added "+id))
 	}
 
 	if len(addedIDs) > 0 {
-		fmt.Printf("\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d skill(s) added", len(addedIDs))))
+		fmt.Fprintf(out, "\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d skill(s) added", len(addedIDs))))
 	}
-	return addedIDs
+	if len(errs) > 0 {
+		return addedIDs, aggregateError(errs, "%d skill(s) failed to import", len(errs))
+	}
+	return addedIDs, nil
 }
 
-// addSkillsFolder scans a directory and lets the user pick skills. Returns added IDs.
+// addSkillsFolder prompts for a directory and lets the user pick skills. Returns added IDs.
 func addSkillsFolder() []string {
-	cm, err := config.NewManager()
-	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return nil
-	}
-	registry := skills.NewRegistry(cm)
-
 	var inputPath string
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -411,77 +464,75 @@ func addSkillsFolder() []string {
 				Value(&inputPath),
 		),
 	)
-	if err := form.Run(); err != nil || inputPath == "" {
+	if err := form.Run(); err != nil || strings.TrimSpace(inputPath) == "" {
 		return nil
 	}
 
+	addedIDs, err := AddFolder(inputPath, interactiveAddOptions())
+	if err != nil && !errors.Is(err, errCancelled) {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+	}
+	return addedIDs
+}
+
+// AddFolder scans a directory for skill subdirectories and copies them into the repo.
+// Returns added IDs.
+func AddFolder(inputPath string, opts AddOptions) ([]string, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+
 	folderPath := resolvePath(strings.TrimSpace(inputPath))
 	if folderPath == "" {
-		return nil
+		return nil, newError(KindValidation, "no folder path given")
 	}
 
 	dirInfo, err := os.Stat(folderPath)
 	if err != nil || !dirInfo.IsDir() {
-		fmt.Println(tui.RenderError("Path does not exist or is not a directory."))
-		return nil
+		return nil, newError(KindNotFound, "path does not exist or is not a directory: %s", folderPath)
 	}
 
 	entries, err := os.ReadDir(folderPath)
 	if err != nil {
-		fmt.Println(tui.RenderError("Error reading directory: " + err.Error()))
-		return nil
+		return nil, fmt.Errorf("error reading directory: %w", err)
 	}
 
-	type skillEntry struct {
-		name string
-		path string
-	}
-	var found []skillEntry
-
+	var found []Candidate
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
 		}
 		if _, err := os.Stat(filepath.Join(folderPath, entry.Name(), "SKILL.md")); err == nil {
-			found = append(found, skillEntry{name: entry.Name(), path: filepath.Join(folderPath, entry.Name())})
+			id := "local:" + entry.Name()
+			found = append(found, Candidate{Name: entry.Name(), ID: id, Installed: registry.GetSkill(id) != nil})
 		}
 	}
 
 	if len(found) == 0 {
-		fmt.Println(tui.RenderWarning("No skills found (no subdirectories with SKILL.md)."))
-		return nil
-	}
-
-	var opts []huh.Option[string]
-	for _, s := range found {
-		id := "local:" + s.name
-		label := s.name
-		if existing := registry.GetSkill(id); existing != nil {
-			label += " " + tui.MutedText.Render("(installed)")
-		}
-		opts = append(opts, huh.NewOption(label, s.name))
+		return nil, newError(KindNotFound, "no skills found (no subdirectories with SKILL.md)")
 	}
 
-	var selected []string
-	if err := huh.NewForm(huh.NewGroup(
-		huh.NewMultiSelect[string]().
-			Title(fmt.Sprintf("Found %d skills — select which to add", len(found))).
-			Options(opts...).
-			Value(&selected),
-	)).Run(); err != nil {
-		return nil
+	selected, err := opts.selectSkills(found)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(selected) == 0 {
-		fmt.Println(tui.MutedText.Render("No skills selected."))
-		return nil
+		fmt.Fprintln(out, tui.MutedText.Render("No skills selected."))
+		return nil, nil
 	}
 
+	op := beginJournal(cm, "add")
+	defer finishJournal(op)
+
 	var addedIDs []string
+	var errs []error
 	for _, selName := range selected {
-		var match *skillEntry
+		var match *Candidate
 		for i := range found {
-			if found[i].name == selName {
+			if found[i].Name == selName {
 				match = &found[i]
 				break
 			}
@@ -490,39 +541,86 @@ func addSkillsFolder() []string {
 			continue
 		}
 
-		id := "local:" + match.name
+		id := match.ID
 
-		if existing := registry.GetSkill(id); existing != nil {
-			var overwrite bool
-			if err := huh.NewForm(huh.NewGroup(
-				huh.NewConfirm().
-					Title(fmt.Sprintf("%s already exists. Overwrite?", id)).
-					Value(&overwrite),
-			)).Run(); err != nil {
+		existing := registry.GetSkill(id)
+		if existing != nil {
+			if !opts.overwrite(id) {
 				continue
 			}
-			if !overwrite {
+			if err := op.SkillReplaced(*existing, cm.GetRepoPath(id)); err != nil {
+				fmt.Fprintln(out, tui.RenderError("Failed: "+match.Name+": "+err.Error()))
+				errs = append(errs, err)
 				continue
 			}
-			os.RemoveAll(cm.GetRepoPath(id))
 		}
 
 		destPath := cm.GetRepoPath(id)
-		fmt.Println(tui.RenderInfo("Copying " + match.name + "..."))
-		if err := copyDir(match.path, destPath); err != nil {
-			fmt.Println(tui.RenderError("Failed: " + match.name + ": " + err.Error()))
+		fmt.Fprintln(out, tui.RenderInfo("Copying "+match.Name+"..."))
+		if err := copyDir(filepath.Join(folderPath, match.Name), destPath); err != nil {
+			fmt.Fprintln(out, tui.RenderError("Failed: "+match.Name+": "+err.Error()))
+			errs = append(errs, err)
 			continue
 		}
 
 		registry.AddSkill(id, "local", "", "")
+		registry.SetSource(id, filepath.Join(folderPath, match.Name))
+		if existing == nil {
+			op.SkillAdded(id)
+		}
 		addedIDs = append(addedIDs, id)
-		fmt.Println(tui.RenderSuccess("Added " + id))
+		fmt.Fprintln(out, tui.RenderSuccess("Added "+id))
 	}
 
 	if len(addedIDs) > 0 {
-		fmt.Printf("\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d skill(s) added", len(addedIDs))))
+		fmt.Fprintf(out, "\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d skill(s) added", len(addedIDs))))
 	}
-	return addedIDs
+	if len(errs) > 0 {
+		return addedIDs, aggregateError(errs, "%d skill(s) failed to import", len(errs))
+	}
+	return addedIDs, nil
+}
+
+// interactiveAddOptions returns AddOptions backed by huh prompts.
+func interactiveAddOptions() AddOptions {
+	return AddOptions{
+		Select:    promptSelectCandidates,
+		Overwrite: confirmOverwrite,
+	}
+}
+
+func promptSelectCandidates(found []Candidate) ([]string, error) {
+	var opts []huh.Option[string]
+	for _, c := range found {
+		label := c.Name
+		if c.Installed {
+			label += " " + tui.MutedText.Render("(installed)")
+		}
+		opts = append(opts, huh.NewOption(label, c.Name))
+	}
+
+	var selected []string
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewMultiSelect[string]().
+			Title(fmt.Sprintf("Found %d skills — select which to add", len(found))).
+			Options(opts...).
+			Value(&selected),
+	)).Run(); err != nil {
+		return nil, errCancelled
+	}
+	return selected, nil
+}
+
+func confirmOverwrite(id string) bool {
+	var overwrite bool
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewConfirm().
+			Title(fmt.Sprintf("%s already exists. Overwrite?", id)).
+			Value(&overwrite),
+	)).Run(); err != nil {
+		return false
+	}
+	return overwrite
 }
 
 // --- helpers ---
@@ -539,7 +637,7 @@ func resolvePath(inputPath string) string {
 	}
 	abs, err := filepath.Abs(inputPath)
 	if err != nil {
-		fmt.Println(tui.RenderError("Cannot resolve path: " + err.Error()))
+		fmt.Fprintln(out, tui.RenderError("Cannot resolve path: "+err.Error()))
 		return ""
 	}
 	return abs
@@ -563,11 +661,21 @@ func copyDir(src, dst string) error {
 		srcPath := filepath.Join(src, entry.Name())
 		dstPath := filepath.Join(dst, entry.Name())
 
-		if entry.IsDir() {
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			// Kept as links, as git archive and the lockfile hash see them.
+			target, err := os.Readlink(srcPath)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(target, dstPath); err != nil {
+				return err
+			}
+		case entry.IsDir():
 			if err := copyDir(srcPath, dstPath); err != nil {
 				return err
 			}
-		} else {
+		default:
 			info, err := entry.Info()
 			if err != nil {
 				return err
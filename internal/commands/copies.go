@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// toolLinkMode returns how skills are installed into p's skill directory.
+func toolLinkMode(p project.Info) (string, error) {
+	switch p.LinkMode {
+	case "", config.LinkModeSymlink:
+		return config.LinkModeSymlink, nil
+	case config.LinkModeCopy, config.LinkModeHardlink:
+		return p.LinkMode, nil
+	}
+	return "", newError(KindConfig, "unknown linkMode %q for %s (expected symlink, copy or hardlink)", p.LinkMode, p.Type)
+}
+
+// linkedCopy returns the provenance of the copy at path if it was installed
+// for a copy or hardlink tool, or nil. Vendored copies and copies still being
+// staged are not included.
+func linkedCopy(path string) *manifest.Provenance {
+	if _, staging := git.StagingPID(filepath.Base(path)); staging {
+		return nil
+	}
+	if info, err := os.Lstat(path); err != nil || !info.IsDir() {
+		return nil
+	}
+	prov, err := manifest.ReadProvenance(path)
+	if err != nil || prov == nil || prov.LinkMode == "" {
+		return nil
+	}
+	return prov
+}
+
+// installedSkillID returns the skill agm installed at path, as a link into
+// agm's repo or a copy for a copy or hardlink tool, or "".
+func installedSkillID(cm *config.Manager, path string) string {
+	if prov := linkedCopy(path); prov != nil {
+		return prov.ID
+	}
+	target, err := readLinkTarget(path)
+	if err != nil {
+		return ""
+	}
+	return linkSkillID(cm, target)
+}
+
+// copySkillToProject is linkSkillToProject for tools whose link mode is copy
+// or hardlink. An existing copy is refreshed when its skill changed, unless it
+// was edited since. It returns false without error when the copy is current.
+func copySkillToProject(op *journal.Op, cm *config.Manager, skill skills.Skill, p project.Info, mode, path string) (bool, error) {
+	registry := skills.NewRegistry(cm)
+	action, prov, err := copyAction(cm, git.NewManager(), skill, p.Root, mode, path)
+	if err != nil {
+		return false, err
+	}
+	if current := linkedCopy(path); current != nil && current.ID == skill.ID {
+		registry.AddCopy(skill.ID, path)
+	}
+	switch action {
+	case "edited":
+		fmt.Fprintln(out, tui.RenderWarning(skill.ID+" in "+p.Type+" was edited since agm copied it; left alone"))
+		recordLink(cm, skill, p, true)
+		return false, nil
+	case "unchanged":
+		recordLink(cm, skill, p, true)
+		return false, nil
+	}
+
+	if err := replaceWithCopy(op, cm, skill, prov, path); err != nil {
+		return false, err
+	}
+	registry.AddCopy(skill.ID, path)
+	recordLink(cm, skill, p, true)
+	if mode == config.LinkModeHardlink {
+		fmt.Fprintln(out, tui.RenderSuccess("Hardlinked "+skill.ID))
+	} else {
+		fmt.Fprintln(out, tui.RenderSuccess("Copied "+skill.ID))
+	}
+	return true, nil
+}
+
+// copyAction decides what copying skill for a copy or hardlink tool does with
+// what is at path: "copy" when nothing is there, "unchanged" when the skill
+// is already copied or vendored there, "edited" for a copy edited since agm
+// made it, which is left alone, and "replace" for an outdated copy or a link,
+// e.g. from before the tool's link mode changed. Anything else is a conflict.
+// It also returns the provenance of a fresh copy.
+func copyAction(cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, root, mode, path string) (string, manifest.Provenance, error) {
+	prov, err := copyProvenance(cm, gitMgr, skill, root, mode)
+	if err != nil {
+		return "", prov, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "copy", prov, nil
+	}
+	current := linkedCopy(path)
+	switch {
+	case current != nil && current.ID == skill.ID:
+		if hash, err := manifest.HashDir(path); err != nil || hash != current.Hash {
+			return "edited", prov, nil
+		}
+		if current.Hash == prov.Hash && current.LinkMode == mode {
+			return "unchanged", prov, nil
+		}
+	case info.IsDir() && isCopyOf(path, skill.ID):
+		return "unchanged", prov, nil
+	case info.Mode().IsRegular() || info.IsDir():
+		return "", prov, newError(KindConflict, "failed to copy %s: %s exists and is not a copy made by agm", skill.ID, path)
+	}
+	return "replace", prov, nil
+}
+
+// refreshCopies brings the copies of skill installed for copy and hardlink
+// tools up to date with agm's repo after the skill was updated, which links
+// get for free. Copies edited since agm made them are left alone, and copies
+// that are gone are forgotten.
+func refreshCopies(op *journal.Op, cm *config.Manager, gitMgr *git.Manager, id string) error {
+	registry := skills.NewRegistry(cm)
+	skill := registry.GetSkill(id)
+	if skill == nil {
+		return nil
+	}
+	var errs []error
+	for _, dir := range skill.Copies {
+		prov := linkedCopy(dir)
+		if prov == nil || prov.ID != skill.ID {
+			registry.RemoveCopy(skill.ID, dir)
+			continue
+		}
+		if hash, err := manifest.HashDir(dir); err != nil || hash != prov.Hash {
+			fmt.Fprintln(out, tui.RenderWarning("Left the copy of "+skill.ID+" in "+filepath.Dir(dir)+" alone: it was edited since agm copied it"))
+			continue
+		}
+		fresh, err := copyProvenance(cm, gitMgr, *skill, "", prov.LinkMode)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if fresh.Hash == prov.Hash {
+			continue
+		}
+		fresh.Source = prov.Source // relative to a project root agm does not know here
+		if err := replaceWithCopy(op, cm, *skill, fresh, dir); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintln(out, tui.MutedText.Render("  refreshed the copy in "+filepath.Dir(dir)))
+	}
+	if len(errs) > 0 {
+		return aggregateError(errs, "%d of the copies of %s could not be refreshed", len(errs), skill.ID)
+	}
+	return nil
+}
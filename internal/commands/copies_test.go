@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestCopyLinkModesFollowSync(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+	setGitIdentity(t)
+
+	work := t.TempDir()
+	mustRunGit(t, work, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(work, "review"))
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v1")
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "v1")
+	remote := filepath.Join(t.TempDir(), "registry.git")
+	mustRunGit(t, work, "clone", "-q", "--bare", work, remote)
+	push := func(content string) {
+		t.Helper()
+		mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), content)
+		mustRunGit(t, work, "commit", "-q", "-am", content)
+		mustRunGit(t, work, "push", "-q", remote, "main")
+		if _, err := Sync(""); err != nil {
+			t.Fatalf("Sync() failed: %v", err)
+		}
+	}
+
+	cm, _ := openConfig()
+	mustWriteFile(t, filepath.Join(cm.GetHomeDir(), "config.json"), `{
+  "registry": "file://`+filepath.ToSlash(remote)+`",
+  "aiTools": [
+    {"type": "claude", "skillDirs": [".claude/skills"], "linkMode": "copy"},
+    {"type": "cursor", "skillDirs": [".cursor/skills"], "linkMode": "hardlink"}
+  ]
+}`)
+	if _, err := Sync(""); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	mustMkdirAll(t, filepath.Join(proj, ".cursor"))
+	projects, err := SelectProjects("", nil, true)
+	if err != nil || len(projects) != 2 {
+		t.Fatalf("SelectProjects() = %+v, %v", projects, err)
+	}
+	if _, err := Link([]string{"registry:review"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	copied := filepath.Join(proj, ".claude", "skills", "review")
+	hardlinked := filepath.Join(proj, ".cursor", "skills", "review")
+	if prov, err := manifest.ReadProvenance(copied); err != nil || prov == nil || prov.LinkMode != config.LinkModeCopy {
+		t.Fatalf("provenance of the copy = %+v, %v", prov, err)
+	}
+	repoFile, _ := os.Stat(filepath.Join(cm.GetRepoPath("registry:review"), "SKILL.md"))
+	if linked, _ := os.Stat(filepath.Join(hardlinked, "SKILL.md")); !os.SameFile(repoFile, linked) {
+		t.Fatal("the hardlink copy's SKILL.md is not a hard link into the repo")
+	}
+	if entries, _ := ListSkills(projects); len(entries) != 1 || len(entries[0].LinkedTools) != 2 {
+		t.Fatalf("ListSkills() = %+v, want the skill installed in both tools", entries)
+	}
+	if report, _ := Status(""); !report.Clean {
+		t.Fatalf("status of fresh copies is not clean: %+v", report.Tools)
+	}
+
+	push("v2")
+	assertFileContent(t, filepath.Join(copied, "SKILL.md"), "v2")
+	assertFileContent(t, filepath.Join(hardlinked, "SKILL.md"), "v2")
+
+	// An edited copy is left alone.
+	mustWriteFile(t, filepath.Join(copied, "SKILL.md"), "local edit")
+	push("v3")
+	assertFileContent(t, filepath.Join(copied, "SKILL.md"), "local edit")
+	assertFileContent(t, filepath.Join(hardlinked, "SKILL.md"), "v3")
+
+	if _, err := Unlink([]string{"registry:review"}, projects[:1]); err != nil {
+		t.Fatalf("Unlink() failed: %v", err)
+	}
+	if _, err := os.Lstat(copied); !os.IsNotExist(err) {
+		t.Fatalf("%s left behind after unlinking", copied)
+	}
+	registry := skills.NewRegistry(cm)
+	if s := registry.GetSkill("registry:review"); len(s.Copies) != 1 || s.Copies[0] != hardlinked {
+		t.Fatalf("tracked copies = %v, want only %s", s.Copies, hardlinked)
+	}
+
+	// A copy of a skill that is gone is broken.
+	registry.RemoveSkill("registry:review")
+	if broken, _ := scanLinks(cm, filepath.Join(proj, ".cursor", "skills")); len(broken) != 1 {
+		t.Fatalf("scanLinks() broken = %v, want the orphaned copy", broken)
+	}
+}
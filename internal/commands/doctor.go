@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// Doctor check statuses.
+const (
+	CheckOK      = "ok"
+	CheckFixed   = "fixed"   // every problem found was repaired
+	CheckWarning = "warning" // problems remain that agm can work around
+	CheckError   = "error"   // problems remain that break agm
+)
+
+// DoctorReport is the outcome of every doctor check.
+type DoctorReport struct {
+	Checks   []DoctorCheck `json:"checks"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Fixed    int           `json:"fixed"`
+}
+
+// DoctorCheck is the outcome of one check.
+type DoctorCheck struct {
+	Name   string        `json:"name"`
+	Status string        `json:"status"`
+	Detail string        `json:"detail,omitempty"`
+	Issues []DoctorIssue `json:"issues"`
+}
+
+// DoctorIssue is one problem found by a check.
+type DoctorIssue struct {
+	Message string `json:"message"`
+	Fixed   bool   `json:"fixed"`
+	Hint    string `json:"hint,omitempty"` // how to repair it by hand
+}
+
+// doctorRun collects the checks of one Doctor call.
+type doctorRun struct {
+	report *DoctorReport
+	fix    bool
+}
+
+// check runs fn and records its issues. Unfixed issues count as errors when
+// severe is set and as warnings otherwise.
+func (d *doctorRun) check(name string, severe bool, fn func(c *DoctorCheck)) {
+	c := DoctorCheck{Name: name, Issues: []DoctorIssue{}}
+	fn(&c)
+
+	c.Status = CheckOK
+	unfixed := 0
+	for _, issue := range c.Issues {
+		if issue.Fixed {
+			d.report.Fixed++
+		} else {
+			unfixed++
+		}
+	}
+	switch {
+	case unfixed > 0 && severe:
+		c.Status = CheckError
+		d.report.Errors += unfixed
+	case unfixed > 0:
+		c.Status = CheckWarning
+		d.report.Warnings += unfixed
+	case len(c.Issues) > 0:
+		c.Status = CheckFixed
+	}
+	d.report.Checks = append(d.report.Checks, c)
+}
+
+// Doctor checks the agm installation for problems. With fix set it repairs
+// what it safely can: registry entries without files are dropped, broken
+// links are removed and the registry clone is reset to its default branch.
+// Repairs to the registry and to links are journaled so agm undo can reverse
+// them; resetting the registry clone is not and discards its local changes
+// for good. It returns an error when errors remain.
+func Doctor(fix bool) (*DoctorReport, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	d := &doctorRun{report: &DoctorReport{Checks: []DoctorCheck{}}, fix: fix}
+	gitMgr := git.NewManager()
+	registry := skills.NewRegistry(cm)
+	var op *journal.Op
+	if fix {
+		op = beginJournal(cm, "doctor")
+		defer finishJournal(op)
+	}
+
+	d.check("git", true, func(c *DoctorCheck) {
+		if err := gitMgr.CheckGitVersion(); err != nil {
+			c.Issues = append(c.Issues, DoctorIssue{Message: err.Error(), Hint: "install git 2.25 or later"})
+		}
+	})
+
+	d.check("config", true, func(c *DoctorCheck) {
+		c.Detail = filepath.Join(cm.GetHomeDir(), "config.json")
+		if _, err := cm.LoadConfig(); err != nil {
+			c.Issues = append(c.Issues, DoctorIssue{
+				Message: "config.json cannot be parsed: " + err.Error(),
+				Hint:    "correct the file, or delete it to restore the defaults",
+			})
+		}
+	})
+
+	registryValid := true
+	d.check("skills.json", true, func(c *DoctorCheck) {
+		c.Detail = filepath.Join(cm.GetRepoDir(), "skills.json")
+		if err := registry.Validate(); err != nil {
+			registryValid = false
+			c.Issues = append(c.Issues, DoctorIssue{
+				Message: "skills.json cannot be parsed: " + err.Error(),
+				Hint:    "correct the file; until then agm sees no installed skills",
+			})
+		}
+	})
+
+	allSkills := registry.GetAllSkills()
+
+	d.check("skill files", true, func(c *DoctorCheck) {
+		for _, skill := range allSkills {
+			repoPath := cm.GetRepoPath(skill.ID)
+			if _, err := os.Stat(repoPath); err == nil {
+				continue
+			}
+			issue := DoctorIssue{
+				Message: skill.ID + " is registered but " + repoPath + " is missing",
+				Hint:    "run agm remove " + skill.ID + " and add it again",
+			}
+			if d.fix {
+				if err := op.SkillRemoved(skill, repoPath); err == nil {
+					registry.RemoveSkill(skill.ID)
+					issue.Fixed = true
+				}
+			}
+			c.Issues = append(c.Issues, issue)
+		}
+	})
+
+	d.check("SKILL.md", false, func(c *DoctorCheck) {
+		for _, skill := range allSkills {
+			dir := skillTargetPath(cm, skill)
+			if _, err := os.Stat(cm.GetRepoPath(skill.ID)); err != nil {
+				continue // reported above
+			}
+			if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err != nil {
+				c.Issues = append(c.Issues, DoctorIssue{
+					Message: skill.ID + " has no SKILL.md in " + dir,
+					Hint:    "AI tools will not load it; remove it with agm remove " + skill.ID,
+				})
+			}
+		}
+	})
+
+	d.check("orphaned directories", false, func(c *DoctorCheck) {
+		if !registryValid {
+			return // every directory would look orphaned
+		}
+		for _, dir := range orphanedRepoDirs(cm, allSkills) {
+			c.Issues = append(c.Issues, DoctorIssue{
+				Message: dir + " is not registered in skills.json",
+				Hint:    "run agm gc to delete it, or add the skill again",
+			})
+		}
+	})
+
+	projects := project.NewDetector("").DetectAll()
+	d.check("links", true, func(c *DoctorCheck) {
+		if len(projects) == 0 {
+			c.Detail = "no AI tools detected in the current directory"
+		}
+		for _, p := range projects {
+			broken, _ := scanLinks(cm, p.SkillDir)
+			for _, name := range broken {
+				linkPath := filepath.Join(p.SkillDir, name)
+				issue := DoctorIssue{Hint: "delete the link"}
+				if prov := linkedCopy(linkPath); prov != nil {
+					issue.Message = linkPath + " is a copy of " + prov.ID + ", which is no longer installed"
+					issue.Hint = "delete the copy, or add the skill again"
+				} else {
+					target, _ := os.Readlink(linkPath)
+					issue.Message = linkPath + " points to missing " + target
+				}
+				issue.Fixed = d.fix && removeBroken(op, cm, linkPath) == nil
+				c.Issues = append(c.Issues, issue)
+			}
+		}
+	})
+
+	d.check("foreign links", false, func(c *DoctorCheck) {
+		for _, p := range projects {
+			_, foreign := scanLinks(cm, p.SkillDir)
+			for _, name := range foreign {
+				linkPath := filepath.Join(p.SkillDir, name)
+				target, _ := os.Readlink(linkPath)
+				c.Issues = append(c.Issues, DoctorIssue{
+					Message: linkPath + " points outside the agm repository to " + target,
+					Hint:    "agm does not manage it; replace it with agm link if the skill should be",
+				})
+			}
+		}
+	})
+
+	d.check("registry clone", false, func(c *DoctorCheck) {
+		registryDir := cm.GetRegistryDir()
+		c.Detail = registryDir
+		if _, err := os.Stat(filepath.Join(registryDir, ".git")); err != nil {
+			c.Detail = "not cloned"
+			return
+		}
+		dirty, err := gitMgr.IsDirty(registryDir)
+		if err != nil {
+			c.Issues = append(c.Issues, DoctorIssue{Message: err.Error(), Hint: "delete " + registryDir + " and run agm sync"})
+			return
+		}
+		if dirty {
+			issue := DoctorIssue{
+				Message: "the registry clone has local changes, which can make agm sync fail",
+				Hint:    "run git reset --hard && git clean -fd in " + registryDir + " (agm doctor --fix does this; the changes are lost and agm undo cannot restore them)",
+			}
+			issue.Fixed = d.fix && gitMgr.Discard(registryDir) == nil
+			c.Issues = append(c.Issues, issue)
+		}
+		if branch, err := gitMgr.CurrentBranch(registryDir); err == nil && branch == "HEAD" {
+			issue := DoctorIssue{
+				Message: "the registry clone is on a detached HEAD, so agm sync cannot pull",
+				Hint:    "check out the default branch in " + registryDir + " (agm doctor --fix does this; agm undo cannot reverse it)",
+			}
+			if d.fix {
+				if def, err := gitMgr.DefaultRemoteBranch(registryDir); err == nil {
+					issue.Fixed = gitMgr.Checkout(registryDir, def) == nil
+				}
+			}
+			c.Issues = append(c.Issues, issue)
+		}
+	})
+
+	if d.report.Errors > 0 {
+		return d.report, newError(KindDrift, "doctor found %d problem(s)", d.report.Errors)
+	}
+	return d.report, nil
+}
+
+// orphanedRepoDirs returns the directories in the repo that belong to no
+// registered skill. Clones being staged are not included.
+func orphanedRepoDirs(cm *config.Manager, allSkills []skills.Skill) []string {
+	entries, err := os.ReadDir(cm.GetRepoDir())
+	if err != nil {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, skill := range allSkills {
+		known[cm.GetSafeName(skill.ID)] = true
+	}
+	var orphans []string
+	for _, entry := range entries {
+		if _, staging := git.StagingPID(entry.Name()); entry.IsDir() && !known[entry.Name()] && !staging {
+			orphans = append(orphans, filepath.Join(cm.GetRepoDir(), entry.Name()))
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// linkSkillID returns the ID of the skill a link target points into, or ""
+// if it is outside the repo. Targets through a project's .agm-home or into
+// another machine's agm home count as the repo.
+func linkSkillID(cm *config.Manager, target string) string {
+	rel, err := filepath.Rel(cm.GetRepoDir(), agmPath(cm, target))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return cm.ParseSafeName(strings.SplitN(filepath.ToSlash(rel), "/", 2)[0])
+}
+
+// Print writes the report in human-readable form.
+func (r *DoctorReport) Print() {
+	for _, c := range r.Checks {
+		line := c.Name
+		if c.Detail != "" {
+			line += " " + tui.MutedText.Render("("+c.Detail+")")
+		}
+		switch c.Status {
+		case CheckOK:
+			fmt.Fprintln(out, tui.SuccessText.Render("  ✓ ")+line)
+		case CheckFixed:
+			fmt.Fprintln(out, tui.SuccessText.Render("  ✓ ")+line+tui.SuccessText.Render(" fixed"))
+		case CheckWarning:
+			fmt.Fprintln(out, tui.WarningText.Render("  ! ")+line)
+		default:
+			fmt.Fprintln(out, tui.ErrorText.Render("  ✗ ")+line)
+		}
+		for _, issue := range c.Issues {
+			if issue.Fixed {
+				fmt.Fprintln(out, tui.SuccessText.Render("      fixed: ")+issue.Message)
+				continue
+			}
+			fmt.Fprintln(out, "      "+issue.Message)
+			if issue.Hint != "" {
+				fmt.Fprintln(out, tui.MutedText.Render("      → "+issue.Hint))
+			}
+		}
+	}
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("%d error(s), %d warning(s)", r.Errors, r.Warnings)
+	if r.Fixed > 0 {
+		summary += fmt.Sprintf(", %d fixed", r.Fixed)
+	}
+	switch {
+	case r.Errors > 0:
+		fmt.Fprintln(out, tui.RenderError(summary))
+	case r.Warnings > 0:
+		fmt.Fprintln(out, tui.RenderWarning(summary))
+	default:
+		fmt.Fprintln(out, tui.RenderSuccess(summary))
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestDoctorFixesMissingSkillsAndBrokenLinks(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	projectDir := t.TempDir()
+	t.Chdir(projectDir)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill("local:gone", "local", "", "")
+	registry.AddSkill("local:kept", "local", "", "")
+	mustMkdirAll(t, cm.GetRepoPath("local:kept"))
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:kept"), "SKILL.md"), "kept")
+	mustMkdirAll(t, filepath.Join(cm.GetRepoDir(), "local__stray"))
+
+	skillDir := filepath.Join(projectDir, ".claude", "skills")
+	mustMkdirAll(t, skillDir)
+	brokenLink := filepath.Join(skillDir, "gone")
+	if err := os.Symlink(cm.GetRepoPath("local:gone"), brokenLink); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+	if err := os.Symlink(projectDir, filepath.Join(skillDir, "elsewhere")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	report, err := Doctor(false)
+	if KindOf(err) != KindDrift {
+		t.Fatalf("Doctor() error = %v, want drift while errors remain", err)
+	}
+	if report.Errors != 2 || report.Warnings != 2 || report.Fixed != 0 {
+		t.Fatalf("Doctor() = %d errors, %d warnings, %d fixed; want 2, 2, 0", report.Errors, report.Warnings, report.Fixed)
+	}
+
+	report, err = Doctor(true)
+	if err != nil {
+		t.Fatalf("Doctor(fix) failed: %v", err)
+	}
+	if report.Errors != 0 || report.Warnings != 2 || report.Fixed != 2 {
+		t.Fatalf("Doctor(fix) = %d errors, %d warnings, %d fixed; want 0, 2, 2", report.Errors, report.Warnings, report.Fixed)
+	}
+	if registry.GetSkill("local:gone") != nil {
+		t.Fatal("expected the entry without files to be removed")
+	}
+	if _, err := os.Lstat(brokenLink); !os.IsNotExist(err) {
+		t.Fatalf("expected broken link to be removed, stat err: %v", err)
+	}
+
+	if _, err := Undo(); err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	if registry.GetSkill("local:gone") == nil {
+		t.Fatal("expected undo to restore the registry entry")
+	}
+	if _, err := os.Lstat(brokenLink); err != nil {
+		t.Fatalf("expected undo to restore the link: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+)
+
+// ErrorKind classifies a failure so callers can react to it.
+type ErrorKind int
+
+const (
+	KindUnknown    ErrorKind = iota
+	KindConfig               // config.json or the agm home directory is unusable
+	KindGit                  // git is missing, too old, or a git command failed
+	KindNetwork              // a remote could not be reached
+	KindValidation           // the input was rejected
+	KindConflict             // the change would clobber something agm does not own
+	KindNotFound             // a skill, tool, project or registry does not exist
+	KindOutdated             // updates are available (not a failure; see Outdated)
+	KindDrift                // a project's skill setup or the installation is inconsistent (see Verify and Doctor)
+)
+
+var kindNames = map[ErrorKind]string{
+	KindUnknown:    "unknown",
+	KindConfig:     "config",
+	KindGit:        "git",
+	KindNetwork:    "network",
+	KindValidation: "validation",
+	KindConflict:   "conflict",
+	KindNotFound:   "not-found",
+	KindOutdated:   "outdated",
+	KindDrift:      "drift",
+}
+
+func (k ErrorKind) String() string {
+	return kindNames[k]
+}
+
+// Error is a classified failure returned from the commands package.
+type Error struct {
+	Kind ErrorKind
+	Msg  string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Msg == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of the first *Error in err's chain, or KindUnknown.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return KindUnknown
+}
+
+func newError(kind ErrorKind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...)}
+}
+
+// classify attaches a kind to err without changing its message.
+func classify(kind ErrorKind, err error) *Error {
+	return &Error{Kind: kind, Err: err}
+}
+
+func wrapError(kind ErrorKind, err error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Msg: fmt.Sprintf(format, args...), Err: err}
+}
+
+// gitError wraps a failed git command, classifying unreachable remotes as
+// network errors.
+func gitError(err error, format string, args ...any) *Error {
+	kind := KindGit
+	if isNetworkFailure(err) || errors.Is(err, context.DeadlineExceeded) {
+		kind = KindNetwork
+	}
+	return wrapError(kind, err, format, args...)
+}
+
+var networkFailures = []string{
+	"could not resolve host",
+	"unable to access",
+	"could not read from remote repository",
+	"connection refused",
+	"connection timed out",
+	"operation timed out",
+	"network is unreachable",
+	"failed to connect",
+}
+
+func isNetworkFailure(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range networkFailures {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateError summarizes the failures of several commands run in turn, as
+// the commands themselves do for the items they handle.
+func AggregateError(errs []error, format string, args ...any) error {
+	return aggregateError(errs, format, args...)
+}
+
+// aggregateError summarizes several failures. When they all share a kind,
+// the summary keeps it.
+func aggregateError(errs []error, format string, args ...any) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	kind := KindOf(errs[0])
+	for _, err := range errs[1:] {
+		if KindOf(err) != kind {
+			kind = KindUnknown
+			break
+		}
+	}
+	return wrapError(kind, errs[0], format, args...)
+}
+
+// openConfig returns a config manager, classifying failures as config errors.
+func openConfig() (*config.Manager, error) {
+	cm, err := config.NewManager()
+	if err != nil {
+		return nil, wrapError(KindConfig, err, "failed to initialize config")
+	}
+	return cm, nil
+}
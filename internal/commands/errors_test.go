@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestKindOfFollowsWrapping(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("outer: %w", newError(KindNotFound, "skill %s not found", "x"))
+	if got := KindOf(err); got != KindNotFound {
+		t.Fatalf("KindOf() = %v, want %v", got, KindNotFound)
+	}
+	if got := KindOf(errors.New("plain")); got != KindUnknown {
+		t.Fatalf("KindOf(plain) = %v, want %v", got, KindUnknown)
+	}
+}
+
+func TestGitErrorClassifiesNetworkFailures(t *testing.T) {
+	t.Parallel()
+
+	offline := errors.New("fatal: unable to access 'https://github.com/org/repo/': Could not resolve host: github.com")
+	if got := gitError(offline, "failed to clone").Kind; got != KindNetwork {
+		t.Fatalf("gitError(offline).Kind = %v, want %v", got, KindNetwork)
+	}
+	broken := errors.New("fatal: not a git repository")
+	if got := gitError(broken, "failed to pull").Kind; got != KindGit {
+		t.Fatalf("gitError(broken).Kind = %v, want %v", got, KindGit)
+	}
+}
+
+func TestAggregateErrorKeepsSharedKind(t *testing.T) {
+	t.Parallel()
+
+	same := []error{newError(KindGit, "a"), newError(KindGit, "b")}
+	if got := KindOf(aggregateError(same, "%d failed", 2)); got != KindGit {
+		t.Fatalf("shared kind = %v, want %v", got, KindGit)
+	}
+	mixed := []error{newError(KindGit, "a"), newError(KindNotFound, "b")}
+	if got := KindOf(aggregateError(mixed, "%d failed", 2)); got != KindUnknown {
+		t.Fatalf("mixed kind = %v, want %v", got, KindUnknown)
+	}
+	if aggregateError(nil, "none") != nil {
+		t.Fatal("expected nil for no errors")
+	}
+}
+
+func TestLinkReportsConflictForExistingDirectory(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	skills.NewRegistry(cm).AddSkill("local:review", "local", "", "")
+	mustMkdirAll(t, cm.GetRepoPath("local:review"))
+
+	skillDir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(skillDir, cm.GetLinkName("local:review")))
+
+	_, err = linkSkillToProject(nil, "local:review", &project.Info{Type: "claude", SkillDir: skillDir})
+	if got := KindOf(err); got != KindConflict {
+		t.Fatalf("KindOf(link error) = %v, want %v (err: %v)", got, KindConflict, err)
+	}
+	if _, statErr := os.Lstat(filepath.Join(skillDir, cm.GetLinkName("local:review"))); statErr != nil {
+		t.Fatalf("expected existing directory to be left alone: %v", statErr)
+	}
+}
@@ -0,0 +1,264 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// tempDirPrefixes name the scratch clones agm makes in os.TempDir, each
+// suffixed with the process ID (see addGitHubSkillsFolder and
+// git.CheckRemoteSkillMd). They leak when the process is killed.
+var tempDirPrefixes = []string{"agm-scan-", "skm-check-"}
+
+// backupGrace keeps journal backups this young, which may belong to an
+// operation that has not been committed yet.
+const backupGrace = time.Hour
+
+// GCOptions selects what GC does beyond deleting orphaned data.
+type GCOptions struct {
+	Git bool // also run git gc in every skill clone and the registry clone
+}
+
+// GCEntry is one directory GC deleted or compacted.
+// Kind is one of orphan, temp, backup or git.
+type GCEntry struct {
+	Kind  string `json:"kind"`
+	Path  string `json:"path"`
+	Size  int64  `json:"size"` // bytes reclaimed
+	Error string `json:"error,omitempty"`
+}
+
+// GCResult summarizes a garbage collection.
+type GCResult struct {
+	Entries   []GCEntry `json:"entries"`
+	Reclaimed int64     `json:"reclaimed"`
+	Failed    int       `json:"failed"`
+
+	errs []error
+}
+
+// GC deletes repo directories without a skills.json entry, scratch and staged
+// clones left behind by killed agm processes, and journal backups no entry
+// refers to. Deleted data cannot be restored with agm undo.
+func GC(opts GCOptions) (*GCResult, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := gcCandidates(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &GCResult{Entries: []GCEntry{}}
+	for _, c := range candidates {
+		if err := os.RemoveAll(c.Path); err != nil {
+			c.Error = err.Error()
+			result.Failed++
+			result.errs = append(result.errs, err)
+			fmt.Fprintln(out, tui.RenderError("  ✗ "+c.Path+": "+err.Error()))
+		} else {
+			result.Reclaimed += c.Size
+			fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("  - %s %s", c.Kind, c.Path))+tui.MutedText.Render(" ("+formatSize(c.Size)+")"))
+		}
+		result.Entries = append(result.Entries, c)
+	}
+
+	if opts.Git {
+		gitMgr := git.NewManager()
+		for _, repoDir := range gitClones(cm) {
+			before := dirSize(filepath.Join(repoDir, ".git"))
+			entry := GCEntry{Kind: "git", Path: repoDir}
+			if err := gitMgr.GC(repoDir); err != nil {
+				err = gitError(err, "git gc failed in %s", repoDir)
+				entry.Error = err.Error()
+				result.Failed++
+				result.errs = append(result.errs, err)
+				fmt.Fprintln(out, tui.RenderError("  ✗ "+err.Error()))
+			} else {
+				entry.Size = max(before-dirSize(filepath.Join(repoDir, ".git")), 0)
+				result.Reclaimed += entry.Size
+				fmt.Fprintln(out, tui.RenderSuccess("  * git gc "+repoDir)+tui.MutedText.Render(" ("+formatSize(entry.Size)+")"))
+			}
+			result.Entries = append(result.Entries, entry)
+		}
+	}
+
+	if len(result.Entries) == 0 {
+		fmt.Fprintln(out, tui.RenderSuccess("Nothing to clean up"))
+	} else {
+		fmt.Fprintln(out, tui.RenderSuccess("Reclaimed "+formatSize(result.Reclaimed)))
+	}
+	if result.Failed > 0 {
+		return result, aggregateError(result.errs, "%d item(s) could not be cleaned up", result.Failed)
+	}
+	return result, nil
+}
+
+// PlanGC returns what GC would delete and compact.
+func PlanGC(opts GCOptions) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := gcCandidates(cm)
+	if err != nil {
+		return nil, err
+	}
+	plan := newPlan("gc")
+	for _, c := range candidates {
+		plan.add("delete", "", c.Path, c.Kind+", "+formatSize(c.Size))
+	}
+	if opts.Git {
+		for _, repoDir := range gitClones(cm) {
+			plan.add("gc", "", repoDir, "git gc")
+		}
+	}
+	return plan, nil
+}
+
+// gcCandidates lists the directories GC deletes, with their sizes.
+func gcCandidates(cm *config.Manager) ([]GCEntry, error) {
+	registry := skills.NewRegistry(cm)
+	if err := registry.Validate(); err != nil {
+		// Every skill directory would look orphaned.
+		return nil, wrapError(KindConfig, err, "skills.json cannot be parsed; run agm doctor")
+	}
+
+	var entries []GCEntry
+	for _, dir := range orphanedRepoDirs(cm, registry.GetAllSkills()) {
+		entries = append(entries, GCEntry{Kind: "orphan", Path: dir, Size: dirSize(dir)})
+	}
+
+	parents := []string{os.TempDir(), cm.GetRepoDir(), cm.GetHomeDir()}
+	for _, p := range project.NewDetector("").DetectAll() {
+		parents = append(parents, p.SkillDir) // skill copies staged by agm vendor
+	}
+	for _, parent := range parents {
+		found, _ := os.ReadDir(parent)
+		for _, e := range found {
+			if pid, ok := tempDirPID(e.Name()); e.IsDir() && ok && !processAlive(pid) {
+				dir := filepath.Join(parent, e.Name())
+				entries = append(entries, GCEntry{Kind: "temp", Path: dir, Size: dirSize(dir)})
+			}
+		}
+	}
+
+	backups, err := unreferencedBackups(cm)
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range backups {
+		entries = append(entries, GCEntry{Kind: "backup", Path: dir, Size: dirSize(dir)})
+	}
+	return entries, nil
+}
+
+// tempDirPID returns the process ID in the name of an agm scratch clone or
+// of a clone or skill copy being staged. Our own directories are ignored.
+func tempDirPID(name string) (int, bool) {
+	if pid, ok := git.StagingPID(name); ok {
+		return pid, pid != os.Getpid()
+	}
+	for _, prefix := range tempDirPrefixes {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			pid, err := strconv.Atoi(rest)
+			return pid, err == nil && pid != os.Getpid()
+		}
+	}
+	return 0, false
+}
+
+// processAlive reports whether a process with the given ID is running.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		// FindProcess fails on Windows when there is no such process.
+		p.Release()
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// unreferencedBackups returns the journal backup directories that no journal
+// entry refers to, such as those of an operation whose entry was never
+// written. Backups younger than backupGrace are kept.
+func unreferencedBackups(cm *config.Manager) ([]string, error) {
+	j := journal.New(cm)
+	recorded, err := j.Entries()
+	if err != nil {
+		return nil, wrapError(KindConfig, err, "failed to read the journal")
+	}
+	referenced := make(map[string]bool)
+	for _, e := range recorded {
+		for _, c := range e.Changes {
+			if c.Backup != "" {
+				referenced[filepath.Dir(j.BackupPath(c))] = true
+			}
+		}
+	}
+
+	backupsDir := filepath.Join(cm.GetHomeDir(), "journal", "backups")
+	dirs, _ := os.ReadDir(backupsDir)
+	var unreferenced []string
+	for _, d := range dirs {
+		dir := filepath.Join(backupsDir, d.Name())
+		stamp, err := strconv.ParseInt(d.Name(), 10, 64)
+		if !d.IsDir() || err != nil || referenced[dir] || time.Since(time.Unix(0, stamp)) < backupGrace {
+			continue
+		}
+		unreferenced = append(unreferenced, dir)
+	}
+	return unreferenced, nil
+}
+
+// gitClones returns the registered skills' git clones and the registry clone.
+func gitClones(cm *config.Manager) []string {
+	var clones []string
+	for _, skill := range skills.NewRegistry(cm).GetAllSkills() {
+		repoDir := cm.GetRepoPath(skill.ID)
+		if _, err := os.Stat(filepath.Join(repoDir, ".git")); err == nil {
+			clones = append(clones, repoDir)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cm.GetRegistryDir(), ".git")); err == nil {
+		clones = append(clones, cm.GetRegistryDir())
+	}
+	return clones
+}
+
+// dirSize returns the total size of the regular files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestGCRemovesOrphanedData(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	skills.NewRegistry(cm).AddSkill("local:kept", "local", "", "")
+	kept := cm.GetRepoPath("local:kept")
+	mustMkdirAll(t, kept)
+	mustWriteFile(t, filepath.Join(kept, "SKILL.md"), "kept")
+	orphan := filepath.Join(cm.GetRepoDir(), "local__crashed")
+	mustMkdirAll(t, orphan)
+	mustWriteFile(t, filepath.Join(orphan, "SKILL.md"), "orphan")
+
+	// No process has this ID; our own scratch clone must survive.
+	leaked := filepath.Join(tmp, "agm-scan-999999999")
+	mustMkdirAll(t, leaked)
+	mustWriteFile(t, filepath.Join(leaked, "file"), "leaked")
+	ours := filepath.Join(tmp, "skm-check-"+strconv.Itoa(os.Getpid()))
+	mustMkdirAll(t, ours)
+
+	staleBackup := filepath.Join(cm.GetHomeDir(), "journal", "backups", "1000")
+	mustMkdirAll(t, filepath.Join(staleBackup, "local__old"))
+	mustWriteFile(t, filepath.Join(staleBackup, "local__old", "SKILL.md"), "old")
+
+	plan, err := PlanGC(GCOptions{})
+	if err != nil {
+		t.Fatalf("PlanGC() failed: %v", err)
+	}
+	if plan.Changes() != 3 {
+		t.Fatalf("PlanGC() planned %d changes, want 3: %+v", plan.Changes(), plan.Actions)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Fatalf("dry run removed the orphan: %v", err)
+	}
+
+	result, err := GC(GCOptions{})
+	if err != nil {
+		t.Fatalf("GC() failed: %v", err)
+	}
+	if len(result.Entries) != 3 || result.Reclaimed != int64(len("orphan")+len("leaked")+len("old")) {
+		t.Fatalf("GC() = %d entries reclaiming %d bytes", len(result.Entries), result.Reclaimed)
+	}
+	for _, gone := range []string{orphan, leaked, staleBackup} {
+		if _, err := os.Stat(gone); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, stat err: %v", gone, err)
+		}
+	}
+	for _, survivor := range []string{kept, ours} {
+		if _, err := os.Stat(survivor); err != nil {
+			t.Errorf("expected %s to be kept: %v", survivor, err)
+		}
+	}
+}
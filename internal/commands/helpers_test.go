@@ -2,15 +2,24 @@ package commands
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"slices"
 	"testing"
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
 	"github.com/ArdentaCorp/agent-management/internal/project"
 	"github.com/ArdentaCorp/agent-management/internal/skills"
 )
 
+// TestMain keeps an AGM_HOME from the environment from pointing the tests,
+// which isolate themselves by setting HOME, at a real agm home.
+func TestMain(m *testing.M) {
+	os.Unsetenv("AGM_HOME")
+	os.Exit(m.Run())
+}
+
 func TestScanForSkillsOneLevel(t *testing.T) {
 	t.Parallel()
 
@@ -65,7 +74,7 @@ func TestRemoveSkillLinkIfPresent(t *testing.T) {
 
 	mustWriteFile(t, linkPath, "placeholder")
 
-	removed := removeSkillLinkIfPresent(cm, skillID, project.Info{SkillDir: projectDir})
+	removed := removeSkillLinkIfPresent(nil, cm, skillID, project.Info{SkillDir: projectDir})
 	if !removed {
 		t.Fatal("expected link cleanup to remove existing entry")
 	}
@@ -73,12 +82,32 @@ func TestRemoveSkillLinkIfPresent(t *testing.T) {
 		t.Fatalf("expected cleaned path to be removed, stat err: %v", err)
 	}
 
-	removed = removeSkillLinkIfPresent(cm, skillID, project.Info{SkillDir: projectDir})
+	removed = removeSkillLinkIfPresent(nil, cm, skillID, project.Info{SkillDir: projectDir})
 	if removed {
 		t.Fatal("expected false when no linked entry exists")
 	}
 }
 
+func TestFindOtherSkillsSkipsCopiesMadeByAgm(t *testing.T) {
+	t.Parallel()
+
+	skillDir := t.TempDir()
+	for _, name := range []string{"mine", "vendored", "copied"} {
+		mustMkdirAll(t, filepath.Join(skillDir, name))
+		mustWriteFile(t, filepath.Join(skillDir, name, "SKILL.md"), name)
+	}
+	if err := manifest.WriteProvenance(filepath.Join(skillDir, "vendored"), manifest.Provenance{ID: "local:vendored"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manifest.WriteProvenance(filepath.Join(skillDir, "copied"), manifest.Provenance{ID: "local:copied", LinkMode: config.LinkModeCopy}); err != nil {
+		t.Fatal(err)
+	}
+
+	if others := findOtherSkills(skillDir); !slices.Equal(others, []string{"mine"}) {
+		t.Fatalf("findOtherSkills() = %v, want only the user's own skill", others)
+	}
+}
+
 func TestRemoveSkillsWithLinkName(t *testing.T) {
 	tempHome := t.TempDir()
 	t.Setenv("HOME", tempHome)
@@ -106,7 +135,8 @@ func TestRemoveSkillsWithLinkName(t *testing.T) {
 	linkPath := filepath.Join(projectDir, cm.GetLinkName(githubID))
 	mustWriteFile(t, linkPath, "linked")
 
-	removedSources, removedLinks := removeSkillsWithLinkName(
+	removedSources, removedLinks, err := removeSkillsWithLinkName(
+		nil,
 		cm,
 		registry,
 		"create-migration",
@@ -114,6 +144,9 @@ func TestRemoveSkillsWithLinkName(t *testing.T) {
 		[]project.Info{{SkillDir: projectDir}},
 	)
 
+	if err != nil {
+		t.Fatalf("removeSkillsWithLinkName() failed: %v", err)
+	}
 	if removedSources != 2 {
 		t.Fatalf("removedSources = %d, want 2", removedSources)
 	}
@@ -167,3 +200,23 @@ func assertFileContent(t *testing.T, path, want string) {
 		t.Fatalf("file %s content = %q, want %q", path, string(data), want)
 	}
 }
+
+func mustRunGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, out)
+	}
+}
+
+// setGitIdentity lets tests commit without a global git config.
+func setGitIdentity(t *testing.T) {
+	t.Helper()
+	for _, v := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(v, "agm")
+	}
+	for _, v := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(v, "agm@example.com")
+	}
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// HookNames are the git hooks agm hooks install writes.
+var HookNames = []string{"post-checkout", "post-merge"}
+
+// hookMarker identifies a hook written by agm.
+const hookMarker = "# agm hooks: keeps the project's skill links in sync"
+
+// hookProjectPrefix starts the line of agm's hook naming its project's path
+// relative to the top of the work tree.
+const hookProjectPrefix = "# agm project: "
+
+// origSuffix is appended to a hook that existed before agm's, which agm's
+// hook runs first and uninstall puts back.
+const origSuffix = ".agm-orig"
+
+// Hook statuses.
+const (
+	HookInstalled = "installed" // written where there was no hook
+	HookChained   = "chained"   // written in front of an existing hook
+	HookUpdated   = "updated"   // agm's hook rewritten
+	HookUnchanged = "unchanged" // agm's hook already current
+	HookRestored  = "restored"  // the original hook put back
+	HookRemoved   = "removed"   // agm's hook deleted; there was no original
+	HookSkipped   = "skipped"   // not agm's hook, left alone
+)
+
+// HooksResult lists what agm hooks install or uninstall did to each hook.
+type HooksResult struct {
+	Project  string       `json:"project"`
+	HooksDir string       `json:"hooksDir"`
+	Hooks    []HookResult `json:"hooks"`
+
+	// rel is Project relative to the top of its work tree.
+	rel string
+}
+
+// HookResult is the outcome for one hook.
+type HookResult struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// InstallHooks writes post-checkout and post-merge hooks into the git
+// repository of the project in dir (the current directory if empty). After a
+// branch switch or a pull they run agm install --prune for the project, with
+// --frozen when it has agm.lock, so links follow the checked-out .agm.json.
+// The hooks directory is shared by every worktree, so the hook finds the
+// project in the work tree it runs in. An existing hook is kept and run first;
+// its exit status is the hook's. A repository holds agm hooks for one project:
+// installing them for another is a conflict.
+func InstallHooks(dir string) (*HooksResult, error) {
+	result, err := openHooks(dir)
+	if err != nil {
+		return nil, err
+	}
+	agm, err := os.Executable()
+	if err != nil {
+		agm = "agm"
+	}
+	if err := os.MkdirAll(result.HooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", result.HooksDir, err)
+	}
+
+	for _, name := range HookNames {
+		hook := HookResult{Name: name, Path: filepath.Join(result.HooksDir, name), Status: HookInstalled}
+		script := hookScript(name, agm, result.rel)
+		existing, err := os.ReadFile(hook.Path)
+		switch {
+		case err == nil && isAgmHook(existing):
+			if other, ok := hookProject(existing); ok && other != result.rel {
+				return result, newError(KindConflict, "%s already runs agm for %s; run agm hooks uninstall there first", hook.Path, other)
+			}
+			hook.Status = HookUpdated
+			if bytes.Equal(existing, script) {
+				result.Hooks = append(result.Hooks, HookResult{Name: name, Path: hook.Path, Status: HookUnchanged})
+				continue
+			}
+		case err == nil:
+			if pathExists(hook.Path + origSuffix) {
+				return result, newError(KindConflict, "%s and %s both exist; remove one to install agm's hook", hook.Path, hook.Path+origSuffix)
+			}
+			if err := os.Rename(hook.Path, hook.Path+origSuffix); err != nil {
+				return result, fmt.Errorf("failed to move %s aside: %w", hook.Path, err)
+			}
+			hook.Status = HookChained
+		case !os.IsNotExist(err):
+			return result, fmt.Errorf("failed to read %s: %w", hook.Path, err)
+		}
+		if err := os.WriteFile(hook.Path, script, 0755); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", hook.Path, err)
+		}
+		// WriteFile keeps the mode of a file it overwrites.
+		if err := os.Chmod(hook.Path, 0755); err != nil {
+			return result, err
+		}
+		fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("%s %s", hookVerb(hook.Status), name)))
+		result.Hooks = append(result.Hooks, hook)
+	}
+	if m, err := manifest.Load(result.Project); err == nil && !m.Exists() {
+		fmt.Fprintln(out, tui.RenderWarning("No "+manifest.FileName+" in "+result.Project+" yet; the hooks do nothing until skills are linked"))
+	}
+	return result, nil
+}
+
+// UninstallHooks removes the hooks InstallHooks wrote, putting back the hooks
+// they were chained in front of. Hooks agm did not write are left alone.
+func UninstallHooks(dir string) (*HooksResult, error) {
+	result, err := openHooks(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range HookNames {
+		hook := HookResult{Name: name, Path: filepath.Join(result.HooksDir, name)}
+		existing, err := os.ReadFile(hook.Path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read %s: %w", hook.Path, err)
+		}
+		if !isAgmHook(existing) {
+			hook.Status = HookSkipped
+			fmt.Fprintln(out, tui.MutedText.Render("  "+name+" was not written by agm; left alone"))
+			result.Hooks = append(result.Hooks, hook)
+			continue
+		}
+		if other, ok := hookProject(existing); ok && other != result.rel {
+			hook.Status = HookSkipped
+			fmt.Fprintln(out, tui.MutedText.Render("  "+name+" runs agm for "+other+"; left alone"))
+			result.Hooks = append(result.Hooks, hook)
+			continue
+		}
+		if pathExists(hook.Path + origSuffix) {
+			if err := os.Rename(hook.Path+origSuffix, hook.Path); err != nil {
+				return result, fmt.Errorf("failed to restore %s: %w", hook.Path, err)
+			}
+			hook.Status = HookRestored
+		} else {
+			if err := os.Remove(hook.Path); err != nil {
+				return result, fmt.Errorf("failed to remove %s: %w", hook.Path, err)
+			}
+			hook.Status = HookRemoved
+		}
+		fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("%s %s", hookVerb(hook.Status), name)))
+		result.Hooks = append(result.Hooks, hook)
+	}
+	if len(result.Hooks) == 0 {
+		fmt.Fprintln(out, tui.RenderInfo("No agm hooks installed in "+result.HooksDir))
+	}
+	return result, nil
+}
+
+// openHooks resolves the project in dir and its repository's hooks directory.
+func openHooks(dir string) (*HooksResult, error) {
+	root, err := projectRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	gitMgr := git.NewManager()
+	hooksDir, err := gitMgr.HooksDir(root)
+	if err != nil {
+		return nil, newError(KindNotFound, "%s is not in a git repository", root)
+	}
+	top, err := gitMgr.TopLevel(root)
+	if err != nil {
+		return nil, newError(KindNotFound, "%s is not in a git work tree", root)
+	}
+	rel, err := filepath.Rel(evalSymlinks(top), evalSymlinks(root))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, newError(KindValidation, "%s is outside its work tree %s", root, top)
+	}
+	return &HooksResult{Project: root, HooksDir: hooksDir, Hooks: []HookResult{}, rel: filepath.ToSlash(rel)}, nil
+}
+
+// evalSymlinks returns path with symlinks resolved, or path if it cannot.
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// hookProject returns the project agm's hook runs for, relative to the top
+// of the work tree. Hooks written before it was recorded report false.
+func hookProject(content []byte) (string, bool) {
+	for _, line := range strings.Split(string(content), "\n") {
+		if rel, ok := strings.CutPrefix(line, hookProjectPrefix); ok {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+func isAgmHook(content []byte) bool {
+	return bytes.Contains(content, []byte(hookMarker))
+}
+
+func hookVerb(status string) string {
+	return strings.ToUpper(status[:1]) + status[1:]
+}
+
+// hookScript returns the hook that runs the original hook, if any, and then
+// agm install --prune for the project at rel in the work tree the hook runs
+// in. post-checkout skips checkouts of single files, which git reports with a
+// third argument of 0.
+func hookScript(name, agm, rel string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#!/bin/sh\n%s\n%s%s\n", hookMarker, hookProjectPrefix, rel)
+	b.WriteString("# Written by agm hooks install; agm hooks uninstall restores the previous hook.\n")
+	fmt.Fprintf(&b, "status=0\nif [ -x \"$0%s\" ]; then\n\t\"$0%s\" \"$@\" || status=$?\nfi\n", origSuffix, origSuffix)
+	if name == "post-checkout" {
+		b.WriteString("[ \"$3\" = 0 ] && exit $status\n")
+	}
+	b.WriteString("top=$(git rev-parse --show-toplevel) || exit $status\n")
+	fmt.Fprintf(&b, "project=\"$top\"/%s\n", shellQuote(rel))
+	fmt.Fprintf(&b, "agm=%s\n[ -x \"$agm\" ] || agm=agm\n", shellQuote(agm))
+	fmt.Fprintf(&b, "if [ -f \"$project/%s\" ]; then\n", manifest.FileName)
+	fmt.Fprintf(&b, "\tfrozen=\n\t[ -f \"$project/%s\" ] && frozen=--frozen\n", manifest.LockFileName)
+	b.WriteString("\t\"$agm\" install --prune $frozen --project \"$project\" || echo \"agm: skill links are out of sync; run agm status\" >&2\n")
+	b.WriteString("fi\nexit $status\n")
+	return []byte(b.String())
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+)
+
+func TestHooksChainAndRestore(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+	mustRunGit(t, proj, "init", "-q")
+
+	hooksDir := filepath.Join(proj, ".git", "hooks")
+	original := "#!/bin/sh\necho mine\n"
+	mustWriteFile(t, filepath.Join(hooksDir, "post-merge"), original)
+
+	result, err := InstallHooks("")
+	if err != nil {
+		t.Fatalf("InstallHooks() failed: %v", err)
+	}
+	statuses := make(map[string]string)
+	for _, h := range result.Hooks {
+		statuses[h.Name] = h.Status
+	}
+	if statuses["post-checkout"] != HookInstalled || statuses["post-merge"] != HookChained {
+		t.Fatalf("statuses = %v", statuses)
+	}
+	assertFileContent(t, filepath.Join(hooksDir, "post-merge"+origSuffix), original)
+	script, _ := os.ReadFile(filepath.Join(hooksDir, "post-checkout"))
+	if !strings.Contains(string(script), "install --prune") {
+		t.Fatalf("hook does not run agm install --prune:\n%s", script)
+	}
+
+	if result, _ = InstallHooks(""); result.Hooks[0].Status != HookUnchanged {
+		t.Fatalf("reinstalling gave %+v, want unchanged", result.Hooks)
+	}
+
+	if _, err := UninstallHooks(""); err != nil {
+		t.Fatalf("UninstallHooks() failed: %v", err)
+	}
+	assertFileContent(t, filepath.Join(hooksDir, "post-merge"), original)
+	for _, name := range []string{"post-checkout", "post-merge" + origSuffix} {
+		if _, err := os.Lstat(filepath.Join(hooksDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after uninstall", name)
+		}
+	}
+}
+
+func TestHooksFollowTheWorktreeTheyRunIn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("runs the hook with sh")
+	}
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	setGitIdentity(t)
+	repo := t.TempDir()
+	mustRunGit(t, repo, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(repo, "web"))
+	mustWriteFile(t, filepath.Join(repo, "web", manifest.FileName), "{}")
+	mustRunGit(t, repo, "add", "-A")
+	mustRunGit(t, repo, "commit", "-q", "-m", "init")
+	worktree := filepath.Join(t.TempDir(), "second")
+	mustRunGit(t, repo, "worktree", "add", "-q", worktree)
+
+	t.Chdir(filepath.Join(repo, "web"))
+	result, err := InstallHooks("")
+	if err != nil {
+		t.Fatalf("InstallHooks() failed: %v", err)
+	}
+	hook := filepath.Join(result.HooksDir, "post-merge")
+	script, _ := os.ReadFile(hook)
+	if strings.Contains(string(script), repo) {
+		t.Fatalf("hook hard-codes the first worktree:\n%s", script)
+	}
+
+	// Run the hook in the second worktree with a stand-in for agm.
+	args := filepath.Join(t.TempDir(), "args")
+	fake := filepath.Join(t.TempDir(), "agm")
+	mustWriteFile(t, fake, "#!/bin/sh\necho \"$@\" > "+shellQuote(args)+"\n")
+	os.Chmod(fake, 0755)
+	mustWriteFile(t, hook, string(hookScript("post-merge", fake, "web")))
+	cmd := exec.Command("sh", hook, "0")
+	cmd.Dir = worktree
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("hook failed: %v\n%s", err, output)
+	}
+	got, _ := os.ReadFile(args)
+	resolved, _ := filepath.EvalSymlinks(worktree)
+	if want := "install --prune --project " + filepath.Join(resolved, "web"); strings.TrimSpace(string(got)) != want {
+		t.Fatalf("hook ran agm %q, want %q", strings.TrimSpace(string(got)), want)
+	}
+
+	// The hooks directory is shared, so another project cannot take it over.
+	mustMkdirAll(t, filepath.Join(repo, "api"))
+	t.Chdir(filepath.Join(repo, "api"))
+	if _, err := InstallHooks(""); KindOf(err) != KindConflict {
+		t.Fatalf("InstallHooks() for a second project = %v, want a conflict", err)
+	}
+	if result, err := UninstallHooks(""); err != nil || result.Hooks[0].Status != HookSkipped {
+		t.Fatalf("UninstallHooks() for a second project = %+v, %v; want web's hooks left alone", result, err)
+	}
+}
+
+func TestPruningInstallDropsUndeclaredLinks(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	for _, name := range []string{"kept", "stale"} {
+		mustMkdirAll(t, filepath.Join(src, name))
+		mustWriteFile(t, filepath.Join(src, name, "SKILL.md"), name)
+	}
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude", "skills", "handmade"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:kept", "local:stale"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	// As if another branch's manifest were checked out.
+	m, _ := manifest.Load(proj)
+	m.Unlink("local:stale", "claude")
+	m.Save()
+
+	result, err := Install(InstallOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Install() failed: %v", err)
+	}
+	if len(result.Pruned) != 1 || result.Pruned[0].SkillID != "local:stale" {
+		t.Fatalf("pruned = %+v, want local:stale", result.Pruned)
+	}
+	skillDir := projects[0].SkillDir
+	for name, want := range map[string]bool{"kept": true, "stale": false, "handmade": true} {
+		if _, err := os.Lstat(filepath.Join(skillDir, name)); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", name, err == nil, want)
+		}
+	}
+}
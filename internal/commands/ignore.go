@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// Ways of keeping agm's links out of git.
+const (
+	IgnoreOff       = "off"       // leave ignore files alone
+	IgnoreGitignore = "gitignore" // list links in the project's .gitignore
+	IgnoreExclude   = "exclude"   // list links in .git/info/exclude
+)
+
+// Lines delimiting the block of ignore patterns agm maintains.
+const (
+	ignoreBegin = "# >>> agm managed links (updated by agm link and unlink; do not edit) >>>"
+	ignoreEnd   = "# <<< agm managed links <<<"
+)
+
+// IgnoreResult describes the managed block of a project's ignore file.
+type IgnoreResult struct {
+	Project string `json:"project"`
+	Mode    string `json:"mode"`
+	// File is the ignore file holding the block; empty when the mode is off.
+	File     string   `json:"file,omitempty"`
+	Patterns []string `json:"patterns"`
+}
+
+// SetIgnoreMode records how the project in dir (the current directory if
+// empty) keeps agm's links out of git and rewrites the managed block to
+// match. An empty mode keeps the recorded one and only refreshes the block.
+func SetIgnoreMode(dir, mode string) (*IgnoreResult, error) {
+	switch mode {
+	case "", IgnoreOff, IgnoreGitignore, IgnoreExclude:
+	default:
+		return nil, newError(KindValidation, "unknown ignore mode %q (expected gitignore, exclude or off)", mode)
+	}
+	root, err := projectRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	if mode != "" && mode != m.IgnoreLinks {
+		if m, err = loadSettingsManifest(cm, root); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", manifest.FileName, err)
+		}
+		m.IgnoreLinks = mode
+		if err := m.Save(); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", manifest.FileName, err)
+		}
+	}
+	result, err := syncIgnoreBlock(cm, root)
+	if err != nil {
+		return result, err
+	}
+	if result.File == "" {
+		fmt.Fprintln(out, tui.RenderInfo("Links are not listed in any ignore file"))
+	} else {
+		fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("%d link(s) listed in %s", len(result.Patterns), result.File)))
+	}
+	return result, nil
+}
+
+// ignoreMode returns the project's ignore mode, falling back to the global
+// setting.
+func ignoreMode(cm *config.Manager, m *manifest.Manifest) string {
+	if m.IgnoreLinks != "" {
+		return m.IgnoreLinks
+	}
+	if mode := cm.GetIgnoreLinks(); mode != "" {
+		return mode
+	}
+	return IgnoreOff
+}
+
+// refreshIgnoreBlock updates the managed block after links in root changed,
+// only warning when it cannot. Projects that do not ignore links are left
+// alone.
+func refreshIgnoreBlock(cm *config.Manager, root string) {
+	if m, err := manifest.Load(root); err == nil && ignoreMode(cm, m) == IgnoreOff {
+		return
+	}
+	if _, err := syncIgnoreBlock(cm, root); err != nil {
+		fmt.Fprintln(out, tui.RenderWarning("Could not update the ignored links: "+err.Error()))
+	}
+}
+
+// syncIgnoreBlock writes the links agm created in root's tool skill
+// directories into the managed block of the ignore file the project's mode
+// selects, and drops the block from the other one.
+func syncIgnoreBlock(cm *config.Manager, root string) (*IgnoreResult, error) {
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, err
+	}
+	result := &IgnoreResult{Project: root, Mode: ignoreMode(cm, m), Patterns: []string{}}
+	gitignore := filepath.Join(root, ".gitignore")
+	exclude, base := "", root
+	gitMgr := git.NewManager()
+	if top, err := gitMgr.TopLevel(root); err == nil {
+		exclude, _ = gitMgr.GitPath(root, "info/exclude")
+		base = top
+	}
+
+	var file string
+	switch result.Mode {
+	case IgnoreOff:
+	case IgnoreGitignore:
+		file, base = gitignore, root
+	case IgnoreExclude:
+		if exclude == "" {
+			return result, newError(KindValidation, "%s is not in a git repository, so it has no .git/info/exclude", root)
+		}
+		file = exclude
+	default:
+		return result, newError(KindConfig, "unknown ignoreLinks mode %q (expected gitignore, exclude or off)", result.Mode)
+	}
+
+	for _, other := range []string{gitignore, exclude} {
+		if other != "" && other != file {
+			if _, err := writeManagedBlock(other, nil); err != nil {
+				return result, err
+			}
+		}
+	}
+	if file == "" {
+		return result, nil
+	}
+	result.File = file
+	result.Patterns = managedLinkPatterns(cm, root, base)
+	_, err = writeManagedBlock(file, result.Patterns)
+	return result, err
+}
+
+// managedLinkPatterns returns an anchored ignore pattern, relative to base,
+// for every link in root's tool skill directories that points into agm's
+// repo, every copy agm made there for a copy or hardlink tool, and root's
+// .agm-home link. Skills agm did not install, and vendored copies, are left
+// out.
+func managedLinkPatterns(cm *config.Manager, root, base string) []string {
+	if resolved, err := filepath.EvalSymlinks(base); err == nil {
+		base = resolved
+	}
+	var patterns []string
+	for _, p := range project.NewDetector(root).DetectAll() {
+		skillDir := p.SkillDir
+		if resolved, err := filepath.EvalSymlinks(skillDir); err == nil {
+			skillDir = resolved
+		}
+		rel, err := filepath.Rel(base, skillDir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue // a skill directory outside the work tree
+		}
+		entries, _ := os.ReadDir(p.SkillDir)
+		for _, entry := range entries {
+			if installedSkillID(cm, filepath.Join(p.SkillDir, entry.Name())) != "" {
+				patterns = append(patterns, "/"+filepath.ToSlash(filepath.Join(rel, entry.Name())))
+			}
+		}
+	}
+	if info, err := os.Lstat(filepath.Join(root, HomeLinkName)); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		resolvedRoot := root
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			resolvedRoot = resolved
+		}
+		if rel, err := filepath.Rel(base, resolvedRoot); err == nil && !strings.HasPrefix(rel, "..") {
+			patterns = append(patterns, "/"+filepath.ToSlash(filepath.Join(rel, HomeLinkName)))
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// writeManagedBlock replaces agm's block in the ignore file at path with
+// patterns, removing the block when there are none. A missing file is only
+// created when there is something to write. It reports whether the file
+// changed.
+func writeManagedBlock(path string, patterns []string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	updated := replaceManagedBlock(string(data), patterns)
+	if updated == string(data) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(updated), 0644)
+}
+
+// replaceManagedBlock returns content with agm's block set to patterns. A new
+// block is appended; an empty one is removed with the blank line before it.
+func replaceManagedBlock(content string, patterns []string) string {
+	var block string
+	if len(patterns) > 0 {
+		block = ignoreBegin + "\n" + strings.Join(patterns, "\n") + "\n" + ignoreEnd + "\n"
+	}
+
+	start := strings.Index(content, ignoreBegin+"\n")
+	end := strings.Index(content, ignoreEnd)
+	if start < 0 || end < start {
+		if block == "" {
+			return content
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		return content + block
+	}
+
+	before := content[:start]
+	after := strings.TrimPrefix(content[end+len(ignoreEnd):], "\n")
+	if block == "" && strings.HasSuffix(before, "\n\n") {
+		before = before[:len(before)-1]
+	}
+	return before + block + after
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+)
+
+func TestReplaceManagedBlock(t *testing.T) {
+	t.Parallel()
+
+	original := "node_modules\n*.log"
+	withBlock := replaceManagedBlock(original, []string{"/.claude/skills/a"})
+	want := "node_modules\n*.log\n\n" + ignoreBegin + "\n/.claude/skills/a\n" + ignoreEnd + "\n"
+	if withBlock != want {
+		t.Fatalf("appending gave %q, want %q", withBlock, want)
+	}
+
+	edited := withBlock + "dist/\n"
+	updated := replaceManagedBlock(edited, []string{"/.claude/skills/a", "/.claude/skills/b"})
+	if !strings.Contains(updated, "/.claude/skills/b\n"+ignoreEnd+"\ndist/\n") || strings.Count(updated, ignoreBegin) != 1 {
+		t.Fatalf("replacing gave %q", updated)
+	}
+
+	if removed := replaceManagedBlock(edited, nil); removed != "node_modules\n*.log\ndist/\n" {
+		t.Fatalf("removing gave %q", removed)
+	}
+	if untouched := replaceManagedBlock(original, nil); untouched != original {
+		t.Fatalf("removing a missing block changed the content to %q", untouched)
+	}
+}
+
+func TestIgnoredLinksFollowLinkAndUnlink(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	for _, name := range []string{"one", "two"} {
+		mustMkdirAll(t, filepath.Join(src, name))
+		mustWriteFile(t, filepath.Join(src, name, "SKILL.md"), name)
+	}
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude", "skills", "handmade"))
+	mustWriteFile(t, filepath.Join(proj, ".gitignore"), "dist/\n")
+
+	if _, err := SetIgnoreMode("", IgnoreGitignore); err != nil {
+		t.Fatalf("SetIgnoreMode() failed: %v", err)
+	}
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:one", "local:two"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	assertFileContent(t, filepath.Join(proj, ".gitignore"),
+		"dist/\n\n"+ignoreBegin+"\n/.claude/skills/one\n/.claude/skills/two\n"+ignoreEnd+"\n")
+
+	if _, err := Unlink([]string{"local:one", "local:two"}, projects); err != nil {
+		t.Fatalf("Unlink() failed: %v", err)
+	}
+	assertFileContent(t, filepath.Join(proj, ".gitignore"), "dist/\n")
+
+	if _, err := SetIgnoreMode("", "svn"); KindOf(err) != KindValidation {
+		t.Fatalf("SetIgnoreMode() with an unknown mode = %v, want a validation error", err)
+	}
+}
+
+func TestIgnoreThenInstallPruneKeepsExistingLinks(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	mustMkdirAll(t, filepath.Join(src, "one"))
+	mustWriteFile(t, filepath.Join(src, "one", "SKILL.md"), "one")
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:one"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	// Links made before the project had a manifest.
+	if err := os.Remove(manifest.Path(proj)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SetIgnoreMode("", IgnoreGitignore); err != nil {
+		t.Fatalf("SetIgnoreMode() failed: %v", err)
+	}
+	if m, err := manifest.Load(proj); err != nil || m.Get("local:one") == nil {
+		t.Fatalf("existing link not declared in the new manifest: %+v, %v", m, err)
+	}
+	result, err := Install(InstallOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("Install() failed: %v", err)
+	}
+	if len(result.Pruned) != 0 {
+		t.Fatalf("Install() pruned %+v", result.Pruned)
+	}
+	if _, err := os.Lstat(filepath.Join(proj, ".claude", "skills", "one")); err != nil {
+		t.Fatalf("link was removed: %v", err)
+	}
+}
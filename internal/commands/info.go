@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// SkillInfo describes one installed skill in detail.
+type SkillInfo struct {
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Metadata skills.Metadata `json:"metadata"`
+	Source   string          `json:"source,omitempty"`
+	Branch   string          `json:"branch,omitempty"`
+	Commit   string          `json:"commit,omitempty"`
+	SubPath  string          `json:"subPath,omitempty"`
+	Path     string          `json:"path"`
+	Size     int64           `json:"size"`
+	Files    []string        `json:"files"`
+	Links    []SkillLink     `json:"links"`
+}
+
+// SkillLink is a place a skill is linked into.
+type SkillLink struct {
+	Project string `json:"project"`
+	Tool    string `json:"tool"`
+	Path    string `json:"path"`
+}
+
+// Info returns the details of a skill, including where it is linked in the
+// given project directories (the current directory if none are given) and
+// every copy of it agm tracks. Every skill directory of every configured
+// tool is checked.
+func Info(id string, projectDirs []string) (*SkillInfo, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	skill := skills.NewRegistry(cm).GetSkill(id)
+	if skill == nil {
+		return nil, newError(KindNotFound, "skill %s not found", id)
+	}
+
+	info := &SkillInfo{
+		ID:      skill.ID,
+		Type:    skill.Type,
+		Commit:  skill.CommitID,
+		SubPath: skill.Path,
+		Path:    skillTargetPath(cm, *skill),
+		Files:   []string{},
+		Links:   []SkillLink{},
+	}
+	info.Metadata, _ = skills.ReadMetadata(info.Path)
+	info.Source, info.Branch = skillSource(cm, *skill)
+
+	err = filepath.WalkDir(info.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(info.Path, path)
+		info.Files = append(info.Files, filepath.ToSlash(rel))
+		info.Size += fi.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", info.Path, err)
+	}
+
+	if len(projectDirs) == 0 {
+		projectDirs = []string{""}
+	}
+	seen := make(map[string]bool)
+	for _, dir := range projectDirs {
+		if dir != "" {
+			resolved := resolvePath(dir)
+			if fi, err := os.Stat(resolved); resolved == "" || err != nil || !fi.IsDir() {
+				return nil, newError(KindNotFound, "project directory %s does not exist", dir)
+			}
+			dir = resolved
+		}
+		detector := project.NewDetector(dir)
+		root := detector.Detect().Root
+		for _, tool := range detector.Tools() {
+			for _, skillDir := range tool.SkillDirs {
+				linkPath := filepath.Join(root, skillDir, cm.GetLinkName(skill.ID))
+				if installedSkillID(cm, linkPath) == skill.ID && !seen[linkPath] {
+					seen[linkPath] = true
+					info.Links = append(info.Links, SkillLink{Project: root, Tool: tool.Type, Path: linkPath})
+				}
+			}
+		}
+	}
+
+	tools := project.NewDetector("").Tools()
+	for _, dir := range skill.Copies {
+		if seen[dir] {
+			continue
+		}
+		if prov := linkedCopy(dir); prov == nil || prov.ID != skill.ID {
+			continue
+		}
+		seen[dir] = true
+		root, tool := copyLocation(tools, dir)
+		info.Links = append(info.Links, SkillLink{Project: root, Tool: tool, Path: dir})
+	}
+	return info, nil
+}
+
+// copyLocation returns the project root and tool whose skill directory holds
+// the copy at dir, or the copy's parent directory and "" if no configured
+// skill directory matches.
+func copyLocation(tools []config.AIToolConfig, dir string) (string, string) {
+	parent := filepath.Dir(dir)
+	for _, tool := range tools {
+		for _, skillDir := range tool.SkillDirs {
+			if root, ok := strings.CutSuffix(parent, string(filepath.Separator)+filepath.Clean(skillDir)); ok {
+				return root, tool.Type
+			}
+		}
+	}
+	return parent, ""
+}
+
+// skillSource returns the URL and branch a skill was installed from.
+func skillSource(cm *config.Manager, skill skills.Skill) (string, string) {
+	gitMgr := git.NewManager()
+	var repoDir string
+	switch skill.Type {
+	case "github":
+		repoDir = cm.GetRepoPath(skill.ID)
+	case "registry":
+		repoDir = cm.GetRegistryDir()
+	default:
+		return "", ""
+	}
+	url, err := gitMgr.RemoteURL(repoDir)
+	if err != nil && skill.Type == "registry" {
+		url = cm.GetRegistry()
+	}
+	branch, _ := gitMgr.CurrentBranch(repoDir)
+	return url, branch
+}
+
+// Print writes the skill details in human-readable form.
+func (i *SkillInfo) Print() {
+	fmt.Fprint(out, tui.RenderSection(i.ID))
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(out, "  %-12s %s\n", label+":", value)
+		}
+	}
+	field("Name", i.Metadata.Name)
+	field("Description", i.Metadata.Description)
+	field("License", i.Metadata.License)
+	field("Type", i.Type)
+	field("Source", i.Source)
+	field("Branch", i.Branch)
+	field("Commit", i.Commit)
+	field("Sub-path", i.SubPath)
+	field("Path", i.Path)
+	field("Size", fmt.Sprintf("%s in %d file(s)", formatSize(i.Size), len(i.Files)))
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "  Files:")
+	for _, f := range i.Files {
+		fmt.Fprintln(out, tui.MutedText.Render("    "+f))
+	}
+
+	fmt.Fprintln(out)
+	if len(i.Links) == 0 {
+		fmt.Fprintln(out, tui.MutedText.Render("  Not linked in the checked project(s)"))
+		return
+	}
+	fmt.Fprintln(out, "  Linked in:")
+	for _, l := range i.Links {
+		fmt.Fprintln(out, "    "+tui.SuccessText.Render(l.Tool)+" "+tui.MutedText.Render(l.Path))
+	}
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestInfoListsFilesAndLinks(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill("local:review", "local", "", "")
+	repoPath := cm.GetRepoPath("local:review")
+	mustMkdirAll(t, filepath.Join(repoPath, "examples"))
+	mustWriteFile(t, filepath.Join(repoPath, "SKILL.md"), "---\nname: review\n---\n")
+	mustWriteFile(t, filepath.Join(repoPath, "examples", "a.md"), "abc")
+
+	projectDir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(projectDir, ".cursor", "skills"))
+	if err := os.Symlink(repoPath, filepath.Join(projectDir, ".cursor", "skills", "review")); err != nil {
+		t.Fatal(err)
+	}
+	// An entry with the skill's name that agm did not put there is no link.
+	mustMkdirAll(t, filepath.Join(projectDir, ".claude", "skills"))
+	mustWriteFile(t, filepath.Join(projectDir, ".claude", "skills", "review"), "someone else's")
+
+	// Copies agm tracks are listed wherever they are.
+	otherDir := t.TempDir()
+	copyDir := filepath.Join(otherDir, ".cursor", "skills", "review")
+	mustMkdirAll(t, copyDir)
+	if err := manifest.WriteProvenance(copyDir, manifest.Provenance{ID: "local:review", LinkMode: config.LinkModeCopy}); err != nil {
+		t.Fatal(err)
+	}
+	registry.AddCopy("local:review", copyDir)
+
+	info, err := Info("local:review", []string{projectDir})
+	if err != nil {
+		t.Fatalf("Info() failed: %v", err)
+	}
+	if info.Metadata.Name != "review" {
+		t.Fatalf("Metadata.Name = %q, want review", info.Metadata.Name)
+	}
+	if want := []string{"SKILL.md", "examples/a.md"}; !slices.Equal(info.Files, want) {
+		t.Fatalf("Files = %v, want %v", info.Files, want)
+	}
+	if info.Size != int64(len("---\nname: review\n---\n")+3) {
+		t.Fatalf("Size = %d", info.Size)
+	}
+	want := []SkillLink{
+		{Project: projectDir, Tool: "cursor", Path: filepath.Join(projectDir, ".cursor", "skills", "review")},
+		{Project: otherDir, Tool: "cursor", Path: copyDir},
+	}
+	if !slices.Equal(info.Links, want) {
+		t.Fatalf("Links = %+v, want %+v", info.Links, want)
+	}
+
+	if _, err := Info("local:missing", nil); KindOf(err) != KindNotFound {
+		t.Fatalf("Info(missing) error kind = %v, want not-found", KindOf(err))
+	}
+}
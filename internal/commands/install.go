@@ -0,0 +1,663 @@
+package commands
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// recordLink adds a link to, or removes it from, the manifest in the
+// project's root, refreshes the skill's entry in the lockfile and the list of
+// ignored links. Files that cannot be updated only produce a warning.
+func recordLink(cm *config.Manager, skill skills.Skill, p project.Info, linked bool) {
+	if p.Root == "" {
+		return
+	}
+	if err := updateManifest(cm, skill, p, linked); err != nil {
+		fmt.Fprintln(out, tui.RenderWarning("Could not update "+manifest.FileName+" or "+manifest.LockFileName+": "+err.Error()))
+	}
+	refreshIgnoreBlock(cm, p.Root)
+}
+
+func updateManifest(cm *config.Manager, skill skills.Skill, p project.Info, linked bool) error {
+	m, err := manifest.Load(p.Root)
+	if err != nil {
+		return err
+	}
+	lock, err := manifest.LoadLock(p.Root)
+	if err != nil {
+		return err
+	}
+	var changed, lockChanged bool
+	if linked {
+		changed = m.Link(skill.ID, manifestSource(cm, skill, p.Root), p.Type)
+		if skill.Type == "registry" && m.Registry == "" {
+			m.Registry = cm.GetRegistry()
+			changed = true
+		}
+		entry, err := lockEntry(cm, git.NewManager(), skill, p.Root)
+		if err != nil {
+			return err
+		}
+		lockChanged = lock.Set(entry)
+	} else {
+		changed = m.Unlink(skill.ID, p.Type)
+		if m.Get(skill.ID) == nil {
+			lockChanged = lock.Remove(skill.ID)
+		}
+	}
+	if changed {
+		if err := m.Save(); err != nil {
+			return err
+		}
+	}
+	if lockChanged {
+		return lock.Save()
+	}
+	return nil
+}
+
+// loadSettingsManifest loads the manifest of the project at root for a
+// command that is about to record a setting in it. When there is none yet,
+// the agm links already in the project are declared first, so that the new
+// manifest does not make install --prune and verify treat them as undeclared.
+func loadSettingsManifest(cm *config.Manager, root string) (*manifest.Manifest, error) {
+	m, err := manifest.Load(root)
+	if err != nil || m.Exists() {
+		return m, err
+	}
+	registry := skills.NewRegistry(cm)
+	for _, p := range project.NewDetector(root).DetectAll() {
+		for _, name := range undeclaredLinks(cm, m, p) {
+			skill := registry.GetSkill(installedSkillID(cm, filepath.Join(p.SkillDir, name)))
+			if skill == nil {
+				continue
+			}
+			if err := updateManifest(cm, *skill, p, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return manifest.Load(root)
+}
+
+// lockEntry returns the exact installed version of skill for the lockfile.
+func lockEntry(cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, root string) (manifest.LockedSkill, error) {
+	entry := manifest.LockedSkill{ID: skill.ID, Source: manifestSource(cm, skill, root)}
+	var err error
+	if entry.Hash, err = manifest.HashDir(skillTargetPath(cm, skill)); err != nil {
+		return entry, fmt.Errorf("failed to hash %s: %w", skill.ID, err)
+	}
+	switch skill.Type {
+	case "github":
+		repoDir := cm.GetRepoPath(skill.ID)
+		if branch, err := gitMgr.CurrentBranch(repoDir); err == nil && branch != "HEAD" {
+			entry.Branch = branch
+		}
+		if entry.Commit, err = gitMgr.GetLocalPathCommitID(repoDir, skillSubPath(skill)); err != nil {
+			return entry, gitError(err, "failed to read %s", skill.ID)
+		}
+	case "registry":
+		entry.Source = cm.GetRegistry()
+		if branch, err := gitMgr.CurrentBranch(cm.GetRegistryDir()); err == nil && branch != "HEAD" {
+			entry.Branch = branch
+		}
+		entry.Commit = skill.CommitID
+	}
+	return entry, nil
+}
+
+// manifestSource returns where a teammate can import skill from: a GitHub URL
+// for github skills, and the original folder for local skills, relative to
+// root when it is inside the project. Registry skills have no source of their
+// own.
+func manifestSource(cm *config.Manager, skill skills.Skill, root string) string {
+	switch skill.Type {
+	case "github":
+		userRepo, ok := githubUserRepo(skill)
+		if !ok {
+			return ""
+		}
+		url := "https://github.com/" + userRepo
+		gitMgr := git.NewManager()
+		repoPath := cm.GetRepoPath(skill.ID)
+		branch, err := gitMgr.CurrentBranch(repoPath)
+		if err == nil && branch == "HEAD" {
+			// A pinned clone is detached; the commit is recorded on its own.
+			branch, err = gitMgr.DefaultRemoteBranch(repoPath)
+		}
+		if err != nil {
+			return url
+		}
+		url += "/tree/" + branch
+		if skill.Path != "" {
+			url += "/" + skill.Path
+		}
+		return url
+	case "local":
+		if skill.Source == "" {
+			return ""
+		}
+		if rel, err := filepath.Rel(root, skill.Source); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+		return skill.Source
+	}
+	return ""
+}
+
+// InstallOptions controls Install.
+type InstallOptions struct {
+	// Dir is the project directory; empty means the current directory.
+	Dir string
+	// Frozen installs exactly the versions in agm.lock and fails when a skill
+	// is not locked or does not match its locked hash.
+	Frozen bool
+	// Prune removes links to agm's skills that the manifest does not declare
+	// for their tool, such as ones left behind by switching branches.
+	Prune bool
+}
+
+// InstallResult summarizes agm install.
+type InstallResult struct {
+	Manifest string   `json:"manifest"`
+	Imported []string `json:"imported"`
+	// Pinned are skills moved to their locked commit by a frozen install.
+	Pinned []string     `json:"pinned"`
+	Links  []LinkResult `json:"links"`
+	// Pruned are undeclared links removed by a pruning install.
+	Pruned []LinkResult `json:"pruned"`
+	// SkippedTools are tools named in the manifest but not detected in the
+	// project.
+	SkippedTools []string `json:"skippedTools"`
+	Failed       int      `json:"failed"`
+
+	errs     []error
+	unpinned []string
+}
+
+func (r *InstallResult) fail(err error) {
+	fmt.Fprintln(out, tui.RenderError(err.Error()))
+	r.Failed++
+	r.errs = append(r.errs, err)
+}
+
+// Install reads the project's manifest, imports every skill it lists that is
+// not installed yet and links each skill into the detected tools the manifest
+// names. Linking records the installed versions in agm.lock; a frozen install
+// instead checks out the locked versions first.
+func Install(opts InstallOptions) (*InstallResult, error) {
+	root, err := projectRoot(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	if !m.Exists() {
+		return nil, newError(KindNotFound, "no %s in %s; link skills to the project to create one", manifest.FileName, root)
+	}
+	var lock *manifest.Lock
+	if opts.Frozen {
+		if lock, err = loadFrozenLock(root, m); err != nil {
+			return nil, err
+		}
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+
+	result := &InstallResult{
+		Manifest:     manifest.Path(root),
+		Imported:     []string{},
+		Pinned:       []string{},
+		Links:        []LinkResult{},
+		Pruned:       []LinkResult{},
+		SkippedTools: []string{},
+	}
+
+	var missing []manifest.Skill
+	for _, s := range m.Skills {
+		if registry.GetSkill(s.ID) == nil {
+			missing = append(missing, s)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprint(out, tui.RenderSection(fmt.Sprintf("Importing %d skill(s)", len(missing))))
+		importManifestSkills(cm, m, root, missing, result)
+	}
+
+	op := beginJournal(cm, "install")
+	defer finishJournal(op)
+	if opts.Frozen {
+		fmt.Fprint(out, tui.RenderSection("Checking locked versions"))
+		gitMgr := git.NewManager()
+		for _, s := range m.Skills {
+			skill := registry.GetSkill(s.ID)
+			if skill == nil {
+				continue // import failed and was reported
+			}
+			pinned, err := pinSkill(op, cm, gitMgr, *skill, *lock.Get(s.ID))
+			if err != nil {
+				result.fail(err)
+				result.unpinned = append(result.unpinned, s.ID)
+				continue
+			}
+			if pinned {
+				fmt.Fprintln(out, tui.RenderSuccess("Pinned "+s.ID+" to "+truncate(lock.Get(s.ID).Commit, 7)))
+				result.Pinned = append(result.Pinned, s.ID)
+			}
+		}
+	}
+
+	detected := make(map[string]project.Info)
+	for _, p := range project.NewDetector(root).DetectAll() {
+		detected[p.Type] = p
+	}
+
+	fmt.Fprint(out, tui.RenderSection("Linking"))
+	linked, unchanged := 0, 0
+	for _, s := range m.Skills {
+		if registry.GetSkill(s.ID) == nil || slices.Contains(result.unpinned, s.ID) {
+			continue // failure already reported
+		}
+		for _, tool := range s.Tools {
+			p, ok := detected[tool]
+			if !ok {
+				if !slices.Contains(result.SkippedTools, tool) {
+					result.SkippedTools = append(result.SkippedTools, tool)
+				}
+				continue
+			}
+			link := LinkResult{SkillID: s.ID, Tool: tool, Path: filepath.Join(p.SkillDir, cm.GetLinkName(s.ID)), Status: "linked"}
+			done, err := linkSkillToProject(op, s.ID, &p)
+			switch {
+			case err != nil:
+				link.Status, link.Error = "failed", err.Error()
+				result.fail(err)
+			case !done:
+				fmt.Fprintln(out, tui.MutedText.Render("  "+s.ID+" unchanged in "+tool))
+				link.Status = "unchanged"
+				unchanged++
+			default:
+				linked++
+			}
+			result.Links = append(result.Links, link)
+		}
+	}
+	for _, tool := range result.SkippedTools {
+		fmt.Fprintln(out, tui.RenderWarning(tool+" is not set up in this project; its skills were not linked"))
+	}
+	if opts.Prune && len(pruneLinks(op, cm, m, detected, result)) > 0 {
+		refreshIgnoreBlock(cm, root)
+	}
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("Install complete: %d skill(s) imported, %d link(s) created, %d unchanged", len(result.Imported), linked, unchanged)
+	if opts.Prune {
+		summary += fmt.Sprintf(", %d pruned", len(result.Pruned))
+	}
+	if result.Failed > 0 {
+		fmt.Fprintln(out, tui.RenderWarning(summary+fmt.Sprintf(", %d failed", result.Failed)))
+		return result, aggregateError(result.errs, "%d skill(s) could not be installed", result.Failed)
+	}
+	fmt.Fprintln(out, tui.RenderSuccess(summary))
+	return result, nil
+}
+
+// pruneLinks removes the links into agm's repo that m does not declare for
+// their tool and returns the ones removed. Skills and directories agm did not
+// link are left alone.
+func pruneLinks(op *journal.Op, cm *config.Manager, m *manifest.Manifest, detected map[string]project.Info, result *InstallResult) []LinkResult {
+	for _, tool := range slices.Sorted(maps.Keys(detected)) {
+		p := detected[tool]
+		for _, name := range undeclaredLinks(cm, m, p) {
+			linkPath := filepath.Join(p.SkillDir, name)
+			skillID := installedSkillID(cm, linkPath)
+			link := LinkResult{SkillID: skillID, Tool: p.Type, Path: linkPath, Status: "pruned"}
+			if err := removeBroken(op, cm, linkPath); err != nil {
+				link.Status, link.Error = "failed", err.Error()
+				result.fail(fmt.Errorf("failed to prune %s: %w", linkPath, err))
+				continue
+			}
+			skills.NewRegistry(cm).RemoveCopy(skillID, linkPath)
+			fmt.Fprintln(out, tui.RenderSuccess("Pruned "+skillID+" from "+p.Type))
+			result.Pruned = append(result.Pruned, link)
+		}
+	}
+	return result.Pruned
+}
+
+// undeclaredLinks returns the names of the links in p's skill directory that
+// point into agm's repo, and of the copies agm made there for a copy or
+// hardlink tool, that are not declared for p's tool in m.
+func undeclaredLinks(cm *config.Manager, m *manifest.Manifest, p project.Info) []string {
+	declared := make(map[string]bool)
+	for _, s := range m.Skills {
+		if slices.Contains(s.Tools, p.Type) {
+			declared[cm.GetLinkName(s.ID)] = true
+		}
+	}
+	entries, _ := os.ReadDir(p.SkillDir)
+	var names []string
+	for _, entry := range entries {
+		if declared[entry.Name()] {
+			continue
+		}
+		if installedSkillID(cm, filepath.Join(p.SkillDir, entry.Name())) != "" {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// PlanInstall returns the skills Install would import and the links it would
+// create.
+func PlanInstall(opts InstallOptions) (*Plan, error) {
+	root, err := projectRoot(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	if !m.Exists() {
+		return nil, newError(KindNotFound, "no %s in %s; link skills to the project to create one", manifest.FileName, root)
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+
+	detected := make(map[string]project.Info)
+	for _, p := range project.NewDetector(root).DetectAll() {
+		detected[p.Type] = p
+	}
+
+	var lock *manifest.Lock
+	if opts.Frozen {
+		if lock, err = loadFrozenLock(root, m); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := newPlan("install")
+	for _, s := range m.Skills {
+		skill := registry.GetSkill(s.ID)
+		if skill == nil {
+			source := s.Source
+			if strings.HasPrefix(s.ID, "registry:") {
+				source = m.Registry
+			}
+			plan.add("add", s.ID, "", source)
+		}
+		if lock != nil {
+			if locked := lock.Get(s.ID); locked.Commit != "" && (skill == nil || skill.CommitID != locked.Commit) {
+				plan.add("update", s.ID, "", "pin to "+truncate(locked.Commit, 7))
+			}
+		}
+		for _, tool := range s.Tools {
+			p, ok := detected[tool]
+			if !ok {
+				continue
+			}
+			linkPath := filepath.Join(p.SkillDir, cm.GetLinkName(s.ID))
+			switch _, err := os.Lstat(linkPath); {
+			case err != nil:
+				plan.add("link", s.ID, linkPath, tool)
+			case !pathExists(linkPath):
+				plan.add("link", s.ID, linkPath, tool+", replaces a broken link")
+			default:
+				plan.add("unchanged", s.ID, linkPath, tool)
+			}
+		}
+	}
+	if opts.Prune {
+		for _, tool := range slices.Sorted(maps.Keys(detected)) {
+			p := detected[tool]
+			for _, name := range undeclaredLinks(cm, m, p) {
+				linkPath := filepath.Join(p.SkillDir, name)
+				plan.add("unlink", installedSkillID(cm, linkPath), linkPath, p.Type+", not in "+manifest.FileName)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// pathExists reports whether path exists, following symlinks.
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// loadFrozenLock reads the lockfile for a frozen install and checks that it
+// pins every skill in the manifest.
+func loadFrozenLock(root string, m *manifest.Manifest) (*manifest.Lock, error) {
+	lock, err := manifest.LoadLock(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read lockfile")
+	}
+	if !lock.Exists() {
+		return nil, newError(KindNotFound, "no %s in %s; run agm install without --frozen to create one", manifest.LockFileName, root)
+	}
+	var unlocked []string
+	for _, s := range m.Skills {
+		if lock.Get(s.ID) == nil {
+			unlocked = append(unlocked, s.ID)
+		}
+	}
+	if len(unlocked) > 0 {
+		return nil, newError(KindValidation, "%s does not pin %s; run agm install without --frozen to update it", manifest.LockFileName, strings.Join(unlocked, ", "))
+	}
+	return lock, nil
+}
+
+// pinSkill moves an installed skill to its locked commit and checks its
+// content hash. It reports whether the skill had to be moved. There is one
+// clone per skill, so this moves it in every project linked to it; the move
+// is journaled.
+func pinSkill(op *journal.Op, cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, locked manifest.LockedSkill) (bool, error) {
+	moved := false
+	switch skill.Type {
+	case "github":
+		repoDir := cm.GetRepoPath(skill.ID)
+		current, err := gitMgr.GetLocalPathCommitID(repoDir, skillSubPath(skill))
+		if err != nil {
+			return false, gitError(err, "failed to read %s", skill.ID)
+		}
+		if locked.Commit != "" && current != locked.Commit {
+			if err := ensureCommit(gitMgr, repoDir, locked.Commit, skill.ID); err != nil {
+				return false, err
+			}
+			head, err := gitMgr.ResolveRef(repoDir, "HEAD")
+			if err != nil {
+				return false, gitError(err, "failed to read %s", skill.ID)
+			}
+			previous, _ := gitMgr.CurrentBranch(repoDir)
+			if previous == "HEAD" {
+				previous = ""
+			}
+			// Stay on a branch so that agm update can still pull.
+			branch := locked.Branch
+			if branch == "" {
+				if branch, err = gitMgr.DefaultRemoteBranch(repoDir); err != nil {
+					branch = previous
+				}
+			}
+			if err := gitMgr.CheckoutAt(repoDir, branch, locked.Commit); err != nil {
+				return false, gitError(err, "failed to check out %s of %s", truncate(locked.Commit, 7), skill.ID)
+			}
+			op.CheckedOut(skill, repoDir, previous, head)
+			skills.NewRegistry(cm).UpdateSkillVersion(skill.ID, locked.Commit)
+			moved = true
+		}
+	case "registry":
+		if locked.Commit != "" && skill.CommitID != locked.Commit {
+			if err := exportRegistrySkill(op, cm, gitMgr, skill, locked.Commit); err != nil {
+				return false, err
+			}
+			moved = true
+		}
+	}
+
+	hash, err := manifest.HashDir(skillTargetPath(cm, skill))
+	if err != nil {
+		return moved, fmt.Errorf("failed to hash %s: %w", skill.ID, err)
+	}
+	if hash != locked.Hash {
+		return moved, newError(KindConflict, "%s does not match %s: its files were changed after it was locked", skill.ID, manifest.LockFileName)
+	}
+	return moved, nil
+}
+
+// ensureCommit fetches repoDir when it does not have commit yet.
+func ensureCommit(gitMgr *git.Manager, repoDir, commit, skillID string) error {
+	if _, err := gitMgr.ResolveRef(repoDir, commit); err == nil {
+		return nil
+	}
+	if err := gitMgr.Fetch(repoDir); err != nil {
+		return gitError(err, "failed to fetch %s", skillID)
+	}
+	if _, err := gitMgr.ResolveRef(repoDir, commit); err != nil {
+		return newError(KindNotFound, "locked commit %s of %s no longer exists upstream", truncate(commit, 7), skillID)
+	}
+	return nil
+}
+
+// exportRegistrySkill replaces a registry skill's copy in the repo with its
+// files at commit in the registry clone.
+func exportRegistrySkill(op *journal.Op, cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, commit string) error {
+	registryDir := cm.GetRegistryDir()
+	if err := ensureCommit(gitMgr, registryDir, commit, skill.ID); err != nil {
+		return err
+	}
+	skillPath := path.Join(gitMgr.NormalizeURL(cm.GetRegistry()).Path, cm.GetLinkName(skill.ID))
+	destPath := cm.GetRepoPath(skill.ID)
+	staging := git.StagingDir(destPath)
+	os.RemoveAll(staging)
+	if err := gitMgr.ExportPath(registryDir, commit, skillPath, staging); err != nil {
+		os.RemoveAll(staging)
+		return gitError(err, "failed to export %s at %s", skill.ID, truncate(commit, 7))
+	}
+	if err := op.SkillReplaced(skill, destPath); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.Rename(staging, destPath); err != nil {
+		return fmt.Errorf("failed to move %s into place: %w", skill.ID, err)
+	}
+	skills.NewRegistry(cm).UpdateSkillVersion(skill.ID, commit)
+	return nil
+}
+
+// projectRoot resolves a project directory argument, defaulting to the
+// current directory.
+func projectRoot(dir string) (string, error) {
+	if dir == "" {
+		return os.Getwd()
+	}
+	resolved := resolvePath(dir)
+	if info, err := os.Stat(resolved); resolved == "" || err != nil || !info.IsDir() {
+		return "", newError(KindNotFound, "project directory %s does not exist", dir)
+	}
+	return resolved, nil
+}
+
+// importManifestSkills imports skills listed in a manifest from their sources.
+// Registry skills are imported with a single sync.
+func importManifestSkills(cm *config.Manager, m *manifest.Manifest, root string, missing []manifest.Skill, result *InstallResult) {
+	registry := skills.NewRegistry(cm)
+	var fromRegistry []manifest.Skill
+	for _, s := range missing {
+		var err error
+		switch skillType, _, _ := strings.Cut(s.ID, ":"); skillType {
+		case "registry":
+			fromRegistry = append(fromRegistry, s)
+			continue
+		case "github":
+			if s.Source == "" {
+				err = newError(KindValidation, "%s has no source in %s", s.ID, manifest.FileName)
+				break
+			}
+			_, err = AddGitHub(s.Source, AddOptions{Select: selectNamed(cm.GetLinkName(s.ID))})
+		case "local":
+			if s.Source == "" {
+				err = newError(KindValidation, "%s has no source in %s", s.ID, manifest.FileName)
+				break
+			}
+			src := filepath.FromSlash(s.Source)
+			if !filepath.IsAbs(src) {
+				src = filepath.Join(root, src)
+			}
+			_, err = AddFolder(filepath.Dir(src), AddOptions{Select: selectNamed(filepath.Base(src))})
+		default:
+			err = newError(KindValidation, "unsupported skill ID %s in %s", s.ID, manifest.FileName)
+		}
+		result.imported(registry, s, err)
+	}
+
+	if len(fromRegistry) == 0 {
+		return
+	}
+	current := cm.GetRegistry()
+	if current != "" && m.Registry != "" && current != m.Registry {
+		err := newError(KindConflict, "the project uses registry %s but agm is set up with %s", m.Registry, current)
+		for _, s := range fromRegistry {
+			result.imported(registry, s, err)
+		}
+		return
+	}
+	if current == "" && m.Registry == "" {
+		err := newError(KindConfig, "%s lists registry skills but names no registry", manifest.FileName)
+		for _, s := range fromRegistry {
+			result.imported(registry, s, err)
+		}
+		return
+	}
+	_, err := Sync(m.Registry)
+	for _, s := range fromRegistry {
+		result.imported(registry, s, err)
+	}
+}
+
+// imported records the outcome of importing s. A skill that is still not
+// registered after a successful import was not found at its source.
+func (r *InstallResult) imported(registry *skills.Registry, s manifest.Skill, err error) {
+	if err == nil && registry.GetSkill(s.ID) == nil {
+		err = newError(KindNotFound, "%s was not found at its source", s.ID)
+	}
+	if err != nil {
+		r.fail(fmt.Errorf("failed to import %s: %w", s.ID, err))
+		return
+	}
+	r.Imported = append(r.Imported, s.ID)
+}
+
+// selectNamed returns an AddOptions.Select that picks the candidate named name.
+func selectNamed(name string) func([]Candidate) ([]string, error) {
+	return func(found []Candidate) ([]string, error) {
+		for _, c := range found {
+			if c.Name == name {
+				return []string{name}, nil
+			}
+		}
+		return nil, nil
+	}
+}
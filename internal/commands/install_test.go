@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestInstallReproducesLinkedSkills(t *testing.T) {
+	firstHome := t.TempDir()
+	t.Setenv("HOME", firstHome)
+	t.Setenv("USERPROFILE", firstHome)
+	setGitIdentity(t)
+
+	work := t.TempDir()
+	mustRunGit(t, work, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(work, "review"))
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "review")
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "init")
+	registryURL := "file://" + filepath.Join(t.TempDir(), "registry.git")
+	mustRunGit(t, work, "clone", "-q", "--bare", work, strings.TrimPrefix(registryURL, "file://"))
+
+	proj := t.TempDir()
+	t.Chdir(proj)
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	mustMkdirAll(t, filepath.Join(proj, "skills", "notes"))
+	mustWriteFile(t, filepath.Join(proj, "skills", "notes", "SKILL.md"), "notes")
+
+	if _, err := AddFolder(filepath.Join(proj, "skills"), AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	if _, err := Sync(registryURL); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:notes", "registry:review"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	m, err := manifest.Load(proj)
+	if err != nil || !m.Exists() {
+		t.Fatalf("manifest not written: %v", err)
+	}
+	if m.Registry != registryURL || len(m.Skills) != 2 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if s := m.Get("local:notes"); s == nil || s.Source != "skills/notes" || !slices.Equal(s.Tools, []string{"claude"}) {
+		t.Fatalf("local skill entry = %+v", s)
+	}
+
+	// A teammate with an empty agm home; the committed links now dangle.
+	os.RemoveAll(firstHome)
+	secondHome := t.TempDir()
+	t.Setenv("HOME", secondHome)
+	t.Setenv("USERPROFILE", secondHome)
+
+	result, err := Install(InstallOptions{})
+	if err != nil {
+		t.Fatalf("Install() failed: %v", err)
+	}
+	if len(result.Imported) != 2 || len(result.Links) != 2 {
+		t.Fatalf("Install() = %+v, want 2 imports and 2 links", result)
+	}
+	for _, name := range []string{"notes", "review"} {
+		target, err := filepath.EvalSymlinks(filepath.Join(proj, ".claude", "skills", name))
+		if err != nil {
+			t.Fatalf("link %s does not resolve: %v", name, err)
+		}
+		if !strings.HasPrefix(target, secondHome) {
+			t.Fatalf("link %s points at %s, want the new home", name, target)
+		}
+	}
+
+	if _, err := Unlink([]string{"local:notes"}, projects); err != nil {
+		t.Fatalf("Unlink() failed: %v", err)
+	}
+	if m, _ := manifest.Load(proj); m.Get("local:notes") != nil {
+		t.Fatal("unlink did not remove the skill from the manifest")
+	}
+}
+
+func TestFrozenInstallPinsLockedVersions(t *testing.T) {
+	firstHome := t.TempDir()
+	t.Setenv("HOME", firstHome)
+	t.Setenv("USERPROFILE", firstHome)
+	setGitIdentity(t)
+
+	work := t.TempDir()
+	mustRunGit(t, work, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(work, "review"))
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v1")
+	if err := os.Symlink("SKILL.md", filepath.Join(work, "review", "README.md")); err != nil {
+		t.Fatal(err)
+	}
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "v1")
+	remote := filepath.Join(t.TempDir(), "registry.git")
+	mustRunGit(t, work, "clone", "-q", "--bare", work, remote)
+
+	proj := t.TempDir()
+	t.Chdir(proj)
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	if _, err := Sync("file://" + remote); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	firstCM, _ := openConfig()
+	if target, err := os.Readlink(filepath.Join(firstCM.GetRepoPath("registry:review"), "README.md")); err != nil || target != "SKILL.md" {
+		t.Fatalf("sync did not keep the symlink in the skill: %q, %v", target, err)
+	}
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"registry:review"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	lock, err := manifest.LoadLock(proj)
+	if err != nil || !lock.Exists() {
+		t.Fatalf("lockfile not written: %v", err)
+	}
+	locked := lock.Get("registry:review")
+	if locked == nil || locked.Commit == "" || locked.Branch != "main" || !strings.HasPrefix(locked.Hash, "sha256:") {
+		t.Fatalf("locked entry = %+v", locked)
+	}
+
+	// The registry moves on before a teammate installs.
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v2")
+	mustRunGit(t, work, "commit", "-q", "-am", "v2")
+	mustRunGit(t, work, "push", "-q", remote, "main")
+	secondHome := t.TempDir()
+	t.Setenv("HOME", secondHome)
+	t.Setenv("USERPROFILE", secondHome)
+
+	result, err := Install(InstallOptions{Frozen: true})
+	if err != nil {
+		t.Fatalf("Install(frozen) failed: %v", err)
+	}
+	if !slices.Equal(result.Pinned, []string{"registry:review"}) {
+		t.Fatalf("pinned = %v, want registry:review", result.Pinned)
+	}
+	assertFileContent(t, filepath.Join(proj, ".claude", "skills", "review", "SKILL.md"), "v1")
+	if again, _ := manifest.LoadLock(proj); *again.Get("registry:review") != *locked {
+		t.Fatalf("frozen install changed the lock: %+v", again.Get("registry:review"))
+	}
+
+	// Local edits no longer match the lock.
+	cm, _ := openConfig()
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("registry:review"), "SKILL.md"), "edited")
+	if _, err := Install(InstallOptions{Frozen: true}); KindOf(err) != KindConflict {
+		t.Fatalf("Install(frozen) of an edited skill = %v, want a conflict", err)
+	}
+}
+
+func TestPinningAGitHubSkillStaysOnABranchAndCanBeUndone(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	setGitIdentity(t)
+
+	work := t.TempDir()
+	mustRunGit(t, work, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(work, "review"))
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v1")
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "v1")
+	gitMgr := git.NewManager()
+	v1, _ := gitMgr.ResolveRef(work, "HEAD")
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v2")
+	mustRunGit(t, work, "commit", "-q", "-am", "v2")
+	v2, _ := gitMgr.ResolveRef(work, "HEAD")
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	id := "github:org/repo/review"
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill(id, "github", v2, "review")
+	repoDir := cm.GetRepoPath(id)
+	mustMkdirAll(t, filepath.Dir(repoDir))
+	mustRunGit(t, work, "clone", "-q", work, repoDir)
+
+	hashDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(hashDir, "SKILL.md"), "v1")
+	hash, err := manifest.HashDir(hashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The lock has no branch, as for a clone that was detached when locked.
+	op := beginJournal(cm, "install")
+	moved, err := pinSkill(op, cm, gitMgr, *registry.GetSkill(id), manifest.LockedSkill{ID: id, Commit: v1, Hash: hash})
+	finishJournal(op)
+	if err != nil || !moved {
+		t.Fatalf("pinSkill() = %v, %v; want the skill moved", moved, err)
+	}
+	if branch, _ := gitMgr.CurrentBranch(repoDir); branch != "main" {
+		t.Fatalf("pinned clone is on %q, want main", branch)
+	}
+	assertFileContent(t, filepath.Join(repoDir, "review", "SKILL.md"), "v1")
+
+	if _, err := Undo(); err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	if head, _ := gitMgr.ResolveRef(repoDir, "HEAD"); head != v2 {
+		t.Fatalf("undo left the clone at %s, want %s", head, v2)
+	}
+	if s := registry.GetSkill(id); s == nil || s.CommitID != v2 {
+		t.Fatalf("registry entry after undo = %+v", s)
+	}
+}
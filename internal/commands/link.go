@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
 	"github.com/ArdentaCorp/agent-management/internal/project"
 	"github.com/ArdentaCorp/agent-management/internal/skills"
 	"github.com/ArdentaCorp/agent-management/internal/tui"
@@ -20,22 +24,22 @@ import (
 func LinkToProject() {
 	cm, err := config.NewManager()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
+		fmt.Fprintln(out, tui.RenderError("Failed to initialize config: "+err.Error()))
 		return
 	}
 	registry := skills.NewRegistry(cm)
 
 	allSkills := registry.GetAllSkills()
 	if len(allSkills) == 0 {
-		fmt.Println(tui.RenderWarning("No skills in repository. Add skills first."))
+		fmt.Fprintln(out, tui.RenderWarning("No skills in repository. Add skills first."))
 		return
 	}
 
 	detector := project.NewDetector("")
 	projects := detector.DetectAll()
 	if len(projects) == 0 {
-		fmt.Println(tui.RenderWarning("No AI tools detected in current directory."))
-		fmt.Println(tui.MutedText.Render("  Supported: .cursor/ .claude/ .codex/ .copilot/ .gemini/"))
+		fmt.Fprintln(out, tui.RenderWarning("No AI tools detected in current directory."))
+		fmt.Fprintln(out, tui.MutedText.Render("  Supported: .cursor/ .claude/ .codex/ .copilot/ .gemini/"))
 		return
 	}
 
@@ -43,7 +47,7 @@ func LinkToProject() {
 	var selectedProjects []project.Info
 	if len(projects) == 1 {
 		selectedProjects = projects
-		fmt.Println(tui.RenderInfo("Detected: " + projects[0].Type))
+		fmt.Fprintln(out, tui.RenderInfo("Detected: "+projects[0].Type))
 	} else {
 		var opts []huh.Option[int]
 		opts = append(opts, huh.NewOption("🔗 All detected tools", -1))
@@ -69,28 +73,33 @@ func LinkToProject() {
 
 	// For each selected tool, run the link flow
 	for _, selectedProject := range selectedProjects {
-		fmt.Print(tui.RenderSection(selectedProject.Type + " Skills"))
-		fmt.Println(tui.MutedText.Render("  " + selectedProject.SkillDir))
+		fmt.Fprint(out, tui.RenderSection(selectedProject.Type+" Skills"))
+		fmt.Fprintln(out, tui.MutedText.Render("  "+selectedProject.SkillDir))
 
 		os.MkdirAll(selectedProject.SkillDir, 0755)
 
 		// Check broken symlinks
-		brokenLinks := findBrokenLinks(selectedProject.SkillDir)
+		brokenLinks, _ := scanLinks(cm, selectedProject.SkillDir)
 		if len(brokenLinks) > 0 {
-			fmt.Println(tui.RenderWarning(fmt.Sprintf("Found %d broken symlink(s)", len(brokenLinks))))
+			fmt.Fprintln(out, tui.RenderWarning(fmt.Sprintf("Found %d broken symlink(s) or copies", len(brokenLinks))))
 			var cleanup bool
 			if err := huh.NewForm(huh.NewGroup(
 				huh.NewConfirm().
-					Title("Remove broken symlinks?").
+					Title("Remove them?").
 					Value(&cleanup),
 			)).Run(); err != nil {
 				return
 			}
 			if cleanup {
+				op := beginJournal(cm, "link")
 				for _, link := range brokenLinks {
-					os.Remove(filepath.Join(selectedProject.SkillDir, link))
-					fmt.Println(tui.RenderSuccess("Removed " + link))
+					if err := removeBroken(op, cm, filepath.Join(selectedProject.SkillDir, link)); err != nil {
+						fmt.Fprintln(out, tui.RenderError("Failed to remove "+link+": "+err.Error()))
+						continue
+					}
+					fmt.Fprintln(out, tui.RenderSuccess("Removed "+link))
 				}
+				finishJournal(op)
 			}
 		}
 
@@ -98,11 +107,11 @@ func LinkToProject() {
 		otherSkills := findOtherSkills(selectedProject.SkillDir)
 		if len(otherSkills) > 0 {
 			sort.Strings(otherSkills)
-			fmt.Println(tui.MutedText.Render("\n  Other skills (not managed by agm):"))
+			fmt.Fprintln(out, tui.MutedText.Render("\n  Other skills (not managed by agm):"))
 			for _, name := range otherSkills {
-				fmt.Println(tui.MutedText.Render("    • " + name))
+				fmt.Fprintln(out, tui.MutedText.Render("    • "+name))
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		// Build multiselect
@@ -140,134 +149,409 @@ func LinkToProject() {
 
 		changes := 0
 		p := selectedProject
+		op := beginJournal(cm, "link")
 		for _, skill := range allSkills {
 			isLinked := linkedSkills[skill.ID]
 			shouldBeLinked := selectedSet[skill.ID]
 
 			if !isLinked && shouldBeLinked {
-				linkSkillToProject(skill.ID, &p)
+				linkOrReport(op, skill.ID, &p)
 				changes++
 			} else if isLinked && !shouldBeLinked {
-				unlinkSkillFromProject(skill.ID, &p)
+				unlinkOrReport(op, skill.ID, &p)
 				changes++
 			}
 		}
+		finishJournal(op)
 
 		if changes == 0 {
-			fmt.Println(tui.MutedText.Render("\nNo changes."))
+			fmt.Fprintln(out, tui.MutedText.Render("\nNo changes."))
 		} else {
-			fmt.Printf("\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d change(s) applied to %s", changes, selectedProject.Type)))
+			fmt.Fprintf(out, "\n%s\n", tui.RenderSuccess(fmt.Sprintf("%d change(s) applied to %s", changes, selectedProject.Type)))
 		}
 	}
 }
 
+// SelectProjects returns the AI tools to operate on in dir (the current
+// directory if empty). Named tools are used even when not yet detected, so
+// their skill directories can be created. Without names, the single detected
+// tool is used, or every detected tool when allTools is set.
+func SelectProjects(dir string, tools []string, allTools bool) ([]project.Info, error) {
+	if dir != "" {
+		resolved := resolvePath(dir)
+		if info, err := os.Stat(resolved); resolved == "" || err != nil || !info.IsDir() {
+			return nil, newError(KindNotFound, "project directory %s does not exist", dir)
+		}
+		dir = resolved
+	}
+	detector := project.NewDetector(dir)
+
+	if len(tools) > 0 {
+		var selected []project.Info
+		for _, tool := range tools {
+			info, ok := detector.ForTool(tool)
+			if !ok {
+				return nil, newError(KindValidation, "unknown tool %s (known: %s)", tool, strings.Join(toolTypes(detector), ", "))
+			}
+			selected = append(selected, info)
+		}
+		return selected, nil
+	}
+
+	projects := detector.DetectAll()
+	if len(projects) == 0 {
+		return nil, newError(KindNotFound, "no AI tools detected in project (supported: .cursor/ .claude/ .codex/ .copilot/ .gemini/)")
+	}
+	if len(projects) > 1 && !allTools {
+		var detected []string
+		for _, p := range projects {
+			detected = append(detected, p.Type)
+		}
+		return nil, newError(KindValidation, "multiple AI tools detected (%s); choose with --tool or --all-tools", strings.Join(detected, ", "))
+	}
+	return projects, nil
+}
+
+func toolTypes(detector *project.Detector) []string {
+	var types []string
+	for _, tool := range detector.Tools() {
+		types = append(types, tool.Type)
+	}
+	return types
+}
+
+// ResolveSkillIDs maps each argument to a registered skill ID.
+// An argument may be a full skill ID or a link name matching exactly one skill.
+func ResolveSkillIDs(args []string) ([]string, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	allSkills := skills.NewRegistry(cm).GetAllSkills()
+
+	var ids []string
+	for _, arg := range args {
+		id, err := resolveSkillID(cm, allSkills, arg)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func resolveSkillID(cm *config.Manager, allSkills []skills.Skill, arg string) (string, error) {
+	var matches []string
+	for _, skill := range allSkills {
+		if skill.ID == arg {
+			return skill.ID, nil
+		}
+		if cm.GetLinkName(skill.ID) == arg {
+			matches = append(matches, skill.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", newError(KindNotFound, "skill %s not found", arg)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", newError(KindValidation, "%s is ambiguous: %s", arg, strings.Join(matches, ", "))
+	}
+}
+
+// LinkResult is the outcome of linking or unlinking one skill in one tool.
+// Status is one of linked, unlinked, unchanged or failed.
+type LinkResult struct {
+	SkillID string `json:"skillId"`
+	Tool    string `json:"tool"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Link symlinks each skill into every given project tool directory.
+// It reports each result and returns an error if any link failed.
+func Link(ids []string, projects []project.Info) ([]LinkResult, error) {
+	return applyLinks(ids, projects, "link", "linked", linkSkillToProject)
+}
+
+// Unlink removes each skill's symlink from every given project tool directory.
+// It reports each result and returns an error if any unlink failed.
+func Unlink(ids []string, projects []project.Info) ([]LinkResult, error) {
+	return applyLinks(ids, projects, "unlink", "unlinked", unlinkSkillFromProject)
+}
+
+func applyLinks(ids []string, projects []project.Info, opName, doneStatus string, apply func(*journal.Op, string, *project.Info) (bool, error)) ([]LinkResult, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	op := beginJournal(cm, opName)
+	defer finishJournal(op)
+
+	results := []LinkResult{}
+	var errs []error
+	for _, p := range projects {
+		fmt.Fprintln(out, tui.RenderInfo(p.Type+" "+tui.MutedText.Render(p.SkillDir)))
+		changed, unchanged, toolFailed := 0, 0, 0
+		for _, id := range ids {
+			result := LinkResult{
+				SkillID: id,
+				Tool:    p.Type,
+				Path:    filepath.Join(p.SkillDir, cm.GetLinkName(id)),
+				Status:  doneStatus,
+			}
+			done, err := apply(op, id, &p)
+			switch {
+			case err != nil:
+				fmt.Fprintln(out, tui.RenderError(err.Error()))
+				result.Status = "failed"
+				result.Error = err.Error()
+				errs = append(errs, err)
+				toolFailed++
+			case !done:
+				fmt.Fprintln(out, tui.MutedText.Render("  "+id+" unchanged"))
+				result.Status = "unchanged"
+				unchanged++
+			default:
+				changed++
+			}
+			results = append(results, result)
+		}
+		fmt.Fprintln(out, tui.MutedText.Render(fmt.Sprintf("  %s: %d %s, %d unchanged, %d failed", p.Type, changed, doneStatus, unchanged, toolFailed)))
+	}
+
+	if len(errs) > 0 {
+		return results, aggregateError(errs, "%d of %d operation(s) failed", len(errs), len(results))
+	}
+	return results, nil
+}
+
 // linkSkillToProject creates a symlink from the global repo to the project.
-func linkSkillToProject(skillID string, projectInfo *project.Info) {
-	cm, err := config.NewManager()
+// It returns false without error when the link already exists.
+func linkSkillToProject(op *journal.Op, skillID string, projectInfo *project.Info) (bool, error) {
+	cm, err := openConfig()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return
+		return false, err
 	}
 	registry := skills.NewRegistry(cm)
 
 	skill := registry.GetSkill(skillID)
 	if skill == nil {
-		fmt.Println(tui.RenderError("Skill " + skillID + " not found."))
-		return
+		return false, newError(KindNotFound, "skill %s not found", skillID)
 	}
 
-	os.MkdirAll(projectInfo.SkillDir, 0755)
+	if err := os.MkdirAll(projectInfo.SkillDir, 0755); err != nil {
+		return false, fmt.Errorf("failed to link %s: %w", skill.ID, err)
+	}
 
 	linkName := cm.GetLinkName(skill.ID)
 	linkPath := filepath.Join(projectInfo.SkillDir, linkName)
-	repoPath := cm.GetRepoPath(skill.ID)
+	mode, err := toolLinkMode(*projectInfo)
+	if err != nil {
+		return false, err
+	}
+	if mode != config.LinkModeSymlink {
+		return copySkillToProject(op, cm, *skill, *projectInfo, mode, linkPath)
+	}
+	strategy, err := projectLinkStrategy(cm, projectInfo.Root)
+	if err != nil {
+		return false, err
+	}
+	targetPath, err := linkTarget(cm, strategy, projectInfo.Root, linkPath, skillTargetPath(cm, *skill))
+	if err != nil {
+		return false, fmt.Errorf("failed to link %s: %w", skill.ID, err)
+	}
 
-	targetPath := repoPath
-	if skill.Path != "" {
-		targetPath = filepath.Join(repoPath, skill.Path)
+	action, err := symlinkAction(cm, *skill, linkPath)
+	switch {
+	case err != nil:
+		return false, err
+	case action == "unchanged":
+		recordLink(cm, *skill, *projectInfo, true)
+		return false, nil
+	case action == "replace":
+		target, _ := os.Readlink(linkPath)
+		if err := os.Remove(linkPath); err != nil {
+			return false, fmt.Errorf("failed to replace broken link %s: %w", linkPath, err)
+		}
+		op.LinkRemoved(skill.ID, linkPath, target)
 	}
 
-	if _, err := os.Lstat(linkPath); err == nil {
-		return // already linked
+	if strategy == LinkHome {
+		if err := ensureHomeLink(op, cm, projectInfo.Root); err != nil {
+			return false, err
+		}
 	}
+	if err := createLink(targetPath, linkPath); err != nil {
+		return false, fmt.Errorf("failed to link %s: %w", skill.ID, err)
+	}
+	op.LinkCreated(skill.ID, linkPath, targetPath)
+	recordLink(cm, *skill, *projectInfo, true)
+
+	fmt.Fprintln(out, tui.RenderSuccess("Linked "+skill.ID))
+	return true, nil
+}
 
+// symlinkAction decides what linking skill does with what is at linkPath:
+// "link" when nothing is there, "unchanged" when the skill is already linked
+// or vendored there, and "replace" for a dangling link, e.g. one committed
+// from another machine. Anything else is a conflict.
+func symlinkAction(cm *config.Manager, skill skills.Skill, linkPath string) (string, error) {
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		return "link", nil
+	}
+	if info.IsDir() && isCopyOf(linkPath, skill.ID) {
+		return "unchanged", nil
+	}
+	if info.Mode().IsRegular() || info.IsDir() {
+		return "", newError(KindConflict, "failed to link %s: %s exists and is not a link", skill.ID, linkPath)
+	}
+	if _, err := os.Stat(linkPath); err != nil {
+		return "replace", nil
+	}
+	if id := installedSkillID(cm, linkPath); id != "" && id != skill.ID {
+		return "", newError(KindConflict, "failed to link %s: %s already links to %s", skill.ID, linkPath, id)
+	}
+	return "unchanged", nil
+}
+
+// createLink links linkPath to targetPath: a symlink, or a directory junction
+// on Windows.
+func createLink(targetPath, linkPath string) error {
 	if runtime.GOOS == "windows" {
 		cmd := exec.Command("cmd", "/c", "mklink", "/J", linkPath, targetPath)
 		if output, err := cmd.CombinedOutput(); err != nil {
-			fmt.Println(tui.RenderError(fmt.Sprintf("Failed to link %s: %v\n%s", skill.ID, err, output)))
-			return
-		}
-	} else {
-		if err := os.Symlink(targetPath, linkPath); err != nil {
-			fmt.Println(tui.RenderError("Failed to link " + skill.ID + ": " + err.Error()))
-			return
+			return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
 		}
+		return nil
 	}
-
-	fmt.Println(tui.RenderSuccess("Linked " + skill.ID))
+	return os.Symlink(targetPath, linkPath)
 }
 
-// unlinkSkillFromProject removes a symlink.
-func unlinkSkillFromProject(skillID string, projectInfo *project.Info) {
-	cm, err := config.NewManager()
+// unlinkSkillFromProject removes a symlink, or a copy made by agm vendor or
+// for a copy or hardlink tool. It returns false without error when there is
+// no link to remove.
+func unlinkSkillFromProject(op *journal.Op, skillID string, projectInfo *project.Info) (bool, error) {
+	cm, err := openConfig()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return
+		return false, err
 	}
 	linkName := cm.GetLinkName(skillID)
 	linkPath := filepath.Join(projectInfo.SkillDir, linkName)
 
-	if _, err := os.Lstat(linkPath); os.IsNotExist(err) {
-		return
+	skill := skills.Skill{ID: skillID}
+	info, err := os.Lstat(linkPath)
+	if os.IsNotExist(err) {
+		recordLink(cm, skill, *projectInfo, false)
+		return false, nil
+	}
+	if err == nil && info.IsDir() && isCopyOf(linkPath, skillID) {
+		if err := op.CopyRemoved(skillID, linkPath); err != nil {
+			return false, fmt.Errorf("failed to remove the copy of %s: %w", skillID, err)
+		}
+		skills.NewRegistry(cm).RemoveCopy(skillID, linkPath)
+		recordLink(cm, skill, *projectInfo, false)
+		fmt.Fprintln(out, tui.RenderSuccess("Removed the copy of "+skillID))
+		return true, nil
 	}
 
+	target, _ := os.Readlink(linkPath)
 	if err := os.Remove(linkPath); err != nil {
-		fmt.Println(tui.RenderError("Failed to unlink " + skillID + ": " + err.Error()))
-		return
+		return false, fmt.Errorf("failed to unlink %s: %w", skillID, err)
+	}
+	op.LinkRemoved(skillID, linkPath, target)
+	recordLink(cm, skill, *projectInfo, false)
+	fmt.Fprintln(out, tui.RenderSuccess("Unlinked "+skillID))
+	return true, nil
+}
+
+// linkOrReport links a skill in an interactive flow, printing any failure.
+func linkOrReport(op *journal.Op, skillID string, projectInfo *project.Info) {
+	if _, err := linkSkillToProject(op, skillID, projectInfo); err != nil {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+	}
+}
+
+// unlinkOrReport unlinks a skill in an interactive flow, printing any failure.
+func unlinkOrReport(op *journal.Op, skillID string, projectInfo *project.Info) {
+	if _, err := unlinkSkillFromProject(op, skillID, projectInfo); err != nil {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
 	}
-	fmt.Println(tui.RenderSuccess("Unlinked " + skillID))
 }
 
 // --- helpers ---
 
+// getLinkedSkills returns the skills installed in skillDir: linked, or copied
+// there with a provenance marker naming the skill.
 func getLinkedSkills(allSkills []skills.Skill, cm *config.Manager, skillDir string) map[string]bool {
 	linked := make(map[string]bool)
 	for _, skill := range allSkills {
 		linkName := cm.GetLinkName(skill.ID)
 		linkPath := filepath.Join(skillDir, linkName)
-		if _, err := os.Lstat(linkPath); err == nil {
+		info, err := os.Lstat(linkPath)
+		if err == nil && (info.Mode()&os.ModeSymlink != 0 || isCopyOf(linkPath, skill.ID)) {
 			linked[skill.ID] = true
 		}
 	}
 	return linked
 }
 
-func findBrokenLinks(skillDir string) []string {
-	var broken []string
+// scanLinks returns the names of the entries in skillDir that are broken —
+// symlinks whose target is missing, and copies made for a copy or hardlink
+// tool of skills that are no longer installed — and of the symlinks pointing
+// outside agm's repo, which agm did not create.
+func scanLinks(cm *config.Manager, skillDir string) (broken, foreign []string) {
 	entries, err := os.ReadDir(skillDir)
 	if err != nil {
-		return broken
+		return nil, nil
+	}
+	repoDir := cm.GetRepoDir()
+	if resolved, err := filepath.EvalSymlinks(repoDir); err == nil {
+		repoDir = resolved
 	}
+	registry := skills.NewRegistry(cm)
 	for _, entry := range entries {
 		linkPath := filepath.Join(skillDir, entry.Name())
 		info, err := os.Lstat(linkPath)
 		if err != nil {
 			continue
 		}
-		if info.Mode()&os.ModeSymlink != 0 {
-			target, err := os.Readlink(linkPath)
-			if err != nil {
-				broken = append(broken, entry.Name())
-				continue
-			}
-			if _, err := os.Stat(target); os.IsNotExist(err) {
+		if info.Mode()&os.ModeSymlink == 0 {
+			if prov := linkedCopy(linkPath); prov != nil && registry.GetSkill(prov.ID) == nil {
 				broken = append(broken, entry.Name())
 			}
+			continue
+		}
+		target, err := filepath.EvalSymlinks(linkPath)
+		if err != nil {
+			broken = append(broken, entry.Name())
+			continue
+		}
+		if rel, err := filepath.Rel(repoDir, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			foreign = append(foreign, entry.Name())
 		}
 	}
-	return broken
+	return broken, foreign
 }
 
+// removeBroken removes an entry scanLinks reported as broken.
+func removeBroken(op *journal.Op, cm *config.Manager, path string) error {
+	if prov := linkedCopy(path); prov != nil {
+		return op.CopyRemoved(prov.ID, path)
+	}
+	target, _ := os.Readlink(path)
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	op.LinkRemoved(linkSkillID(cm, target), path, target)
+	return nil
+}
+
+// findOtherSkills returns the names of the skill directories in skillDir that
+// agm did not put there. Copies with a provenance marker are agm's.
 func findOtherSkills(skillDir string) []string {
 	var others []string
 	entries, err := os.ReadDir(skillDir)
@@ -280,6 +564,12 @@ func findOtherSkills(skillDir string) []string {
 		if err != nil || info.Mode()&os.ModeSymlink != 0 {
 			continue
 		}
+		if _, staging := git.StagingPID(entry.Name()); staging {
+			continue
+		}
+		if prov, _ := manifest.ReadProvenance(entryPath); prov != nil {
+			continue
+		}
 		if info.IsDir() {
 			if _, err := os.Stat(filepath.Join(entryPath, "SKILL.md")); err == nil {
 				others = append(others, entry.Name())
@@ -2,11 +2,13 @@ package commands
 
 import (
 	"fmt"
-	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
 	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/project"
 	"github.com/ArdentaCorp/agent-management/internal/skills"
 	"github.com/ArdentaCorp/agent-management/internal/tui"
 	"github.com/charmbracelet/huh"
@@ -17,7 +19,7 @@ import (
 func ManageSkills() {
 	cm, err := config.NewManager()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
+		fmt.Fprintln(out, tui.RenderError("Failed to initialize config: "+err.Error()))
 		return
 	}
 	registry := skills.NewRegistry(cm)
@@ -26,11 +28,11 @@ func ManageSkills() {
 		allSkills := registry.GetAllSkills()
 
 		if len(allSkills) == 0 {
-			fmt.Println(tui.RenderWarning("No skills installed. Use 'Add skills' first."))
+			fmt.Fprintln(out, tui.RenderWarning("No skills installed. Use 'Add skills' first."))
 			return
 		}
 
-		fmt.Print(tui.RenderSection("Manage Skills"))
+		fmt.Fprint(out, tui.RenderSection("Manage Skills"))
 
 		var opts []huh.Option[string]
 		for _, s := range allSkills {
@@ -66,7 +68,7 @@ func ManageSkills() {
 
 // manageOneSkill shows actions for a single skill.
 func manageOneSkill(skill skills.Skill) {
-	fmt.Print(tui.RenderSection(skill.ID))
+	fmt.Fprint(out, tui.RenderSection(skill.ID))
 
 	var opts []huh.Option[string]
 
@@ -79,13 +81,14 @@ func manageOneSkill(skill skills.Skill) {
 				truncate(update.remoteHead, 7))
 			opts = append(opts, huh.NewOption(label, "update"))
 		} else {
-			fmt.Println(tui.SuccessText.Render("  Up to date"))
+			fmt.Fprintln(out, tui.SuccessText.Render("  Up to date"))
 		}
 	} else {
-		fmt.Println(tui.MutedText.Render("  Local — no remote updates"))
+		fmt.Fprintln(out, tui.MutedText.Render("  Local — no remote updates"))
 	}
 
 	opts = append(opts,
+		huh.NewOption("ℹ️  Info", "info"),
 		huh.NewOption("🗑️  Delete", "delete"),
 		huh.NewOption("← Back", "back"),
 	)
@@ -103,89 +106,344 @@ func manageOneSkill(skill skills.Skill) {
 	switch action {
 	case "update":
 		if update != nil {
-			doUpdate(skill, *update)
+			if err := doUpdate(skill, *update); err != nil {
+				fmt.Fprintln(out, tui.RenderError("Failed: "+err.Error()))
+			}
+		}
+	case "info":
+		info, err := Info(skill.ID, nil)
+		if err != nil {
+			fmt.Fprintln(out, tui.RenderError(err.Error()))
+			return
 		}
+		info.Print()
 	case "delete":
 		doDelete(skill.ID)
 	}
 }
 
+// SkillEntry describes an installed skill and where it is linked.
+type SkillEntry struct {
+	ID          string   `json:"id"`
+	Type        string   `json:"type"`
+	Commit      string   `json:"commit,omitempty"`
+	SubPath     string   `json:"subPath,omitempty"`
+	Path        string   `json:"path"`
+	LinkedTools []string `json:"linkedTools"`
+}
+
+// ListSkills returns all installed skills sorted by ID, with the given
+// project tools each one is linked into.
+func ListSkills(projects []project.Info) ([]SkillEntry, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	allSkills := skills.NewRegistry(cm).GetAllSkills()
+
+	linkedBy := make(map[string][]string)
+	for _, p := range projects {
+		for id := range getLinkedSkills(allSkills, cm, p.SkillDir) {
+			linkedBy[id] = append(linkedBy[id], p.Type)
+		}
+	}
+
+	entries := make([]SkillEntry, 0, len(allSkills))
+	for _, s := range allSkills {
+		linked := linkedBy[s.ID]
+		if linked == nil {
+			linked = []string{}
+		}
+		entries = append(entries, SkillEntry{
+			ID:          s.ID,
+			Type:        s.Type,
+			Commit:      s.CommitID,
+			SubPath:     s.Path,
+			Path:        skillTargetPath(cm, s),
+			LinkedTools: linked,
+		})
+	}
+	return entries, nil
+}
+
+// skillTargetPath returns the on-disk directory a skill's links point at.
+func skillTargetPath(cm *config.Manager, skill skills.Skill) string {
+	if skill.Path != "" {
+		return filepath.Join(cm.GetRepoPath(skill.ID), skill.Path)
+	}
+	return cm.GetRepoPath(skill.ID)
+}
+
+// UpdateResult summarizes an update run.
+type UpdateResult struct {
+	Updated  int                `json:"updated"`
+	UpToDate int                `json:"upToDate"`
+	Skipped  int                `json:"skipped"`
+	Failed   int                `json:"failed"`
+	Skills   []UpdateSkillEntry `json:"skills"`
+}
+
+// UpdateSkillEntry records what an update did to one skill.
+// Action is one of updated, unchanged, skipped or failed.
+type UpdateSkillEntry struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// GitHubSkillIDs returns the IDs of every installed GitHub skill.
+func GitHubSkillIDs() ([]string, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, skill := range skills.NewRegistry(cm).GetAllSkills() {
+		if skill.Type == "github" {
+			ids = append(ids, skill.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Update fetches and pulls the given GitHub skills, at most jobs at a time.
+// Registry skills are refreshed by Sync and local skills have no remote, so
+// both are skipped. A skill that fails does not stop the others.
+func Update(ids []string, jobs int) (*UpdateResult, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+
+	result := &UpdateResult{Skills: []UpdateSkillEntry{}}
+	var remote []skills.Skill
+	for _, id := range ids {
+		skill := registry.GetSkill(id)
+		if skill == nil {
+			return nil, newError(KindNotFound, "skill %s not found", id)
+		}
+		switch skill.Type {
+		case "github":
+			remote = append(remote, *skill)
+		case "registry":
+			result.skip(skill.ID, "registry skills are updated by sync")
+		default:
+			result.skip(skill.ID, "local, no remote updates")
+		}
+	}
+	if len(remote) == 0 {
+		return result, nil
+	}
+
+	fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("Checking %d skill(s) for updates...", len(remote))))
+	gitMgr := git.NewManager()
+	entries := make([]UpdateSkillEntry, len(remote))
+	errs := make([]error, len(remote))
+	finished := 0
+	runParallel(len(remote), jobs, func(i int) {
+		entries[i], errs[i] = pullSkill(cm, gitMgr, remote[i])
+	}, func(i int) {
+		e := entries[i]
+		finished++
+		reportProgress(ProgressEvent{Op: "update", Subject: e.ID, Phase: "Updating skills", Percent: finished * 100 / len(remote), Current: finished, Total: len(remote), Done: finished == len(remote)})
+		switch e.Action {
+		case "updated":
+			registry.UpdateSkillVersion(e.ID, e.To)
+			result.Updated++
+			fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("  ↑ %s (%s → %s)", e.ID, truncate(e.From, 7), truncate(e.To, 7))))
+			if err := refreshCopies(nil, cm, gitMgr, e.ID); err != nil {
+				fmt.Fprintln(out, tui.RenderWarning(err.Error()))
+			}
+		case "unchanged":
+			result.UpToDate++
+			fmt.Fprintln(out, tui.SuccessText.Render("  "+e.ID+" is up to date"))
+		default:
+			result.Failed++
+			fmt.Fprintln(out, tui.RenderError(e.Error))
+		}
+	})
+	result.Skills = append(result.Skills, entries...)
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("Update complete: %d updated, %d up to date", result.Updated, result.UpToDate)
+	if result.Failed > 0 {
+		fmt.Fprintln(out, tui.RenderWarning(summary+fmt.Sprintf(", %d failed", result.Failed)))
+		var failed []error
+		for _, err := range errs {
+			if err != nil {
+				failed = append(failed, err)
+			}
+		}
+		return result, aggregateError(failed, "%d skill(s) failed to update", result.Failed)
+	}
+	fmt.Fprintln(out, tui.RenderSuccess(summary))
+	return result, nil
+}
+
+func (r *UpdateResult) skip(id, reason string) {
+	r.Skipped++
+	r.Skills = append(r.Skills, UpdateSkillEntry{ID: id, Action: "skipped", Detail: reason})
+	fmt.Fprintln(out, tui.MutedText.Render("  "+id+" — "+reason))
+}
+
+// pullSkill fetches a GitHub skill's clone and pulls it when its path has new
+// commits. It prints nothing, so it can run on a worker goroutine.
+func pullSkill(cm *config.Manager, gitMgr *git.Manager, skill skills.Skill) (UpdateSkillEntry, error) {
+	entry := UpdateSkillEntry{ID: skill.ID, Action: "unchanged", From: skill.CommitID}
+	fail := func(err error) (UpdateSkillEntry, error) {
+		entry.Action, entry.Error = "failed", err.Error()
+		return entry, err
+	}
+
+	local, remoteHead, _, err := fetchSkillHeads(cm, gitMgr, skill)
+	if err != nil {
+		return fail(err)
+	}
+	if remoteHead == "" || remoteHead == local {
+		return entry, nil
+	}
+	if err := gitMgr.PullQuiet(cm.GetRepoPath(skill.ID)); err != nil {
+		return fail(gitError(err, "failed to pull %s", skill.ID))
+	}
+	entry.Action, entry.To = "updated", remoteHead
+	return entry, nil
+}
+
+// Remove deletes the given skills from the repository without prompting.
+func Remove(ids []string) error {
+	cm, err := openConfig()
+	if err != nil {
+		return err
+	}
+	registry := skills.NewRegistry(cm)
+
+	for _, id := range ids {
+		if registry.GetSkill(id) == nil {
+			return newError(KindNotFound, "skill %s not found", id)
+		}
+	}
+	op := beginJournal(cm, "delete")
+	defer finishJournal(op)
+	for _, id := range ids {
+		if err := removeSkill(op, cm, registry, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type updateInfo struct {
 	remoteHead string
 	branch     string
 }
 
 func checkForUpdate(skill skills.Skill) *updateInfo {
-	cm, err := config.NewManager()
+	fmt.Fprintln(out, tui.RenderInfo("Checking for updates..."))
+	update, err := findUpdate(skill)
 	if err != nil {
 		return nil
 	}
-	gitMgr := git.NewManager()
+	return update
+}
 
-	parts := strings.SplitN(skill.ID, ":", 2)
-	if len(parts) < 2 {
-		return nil
+// findUpdate fetches a GitHub skill's clone and returns the newer remote commit,
+// or nil when the skill is up to date.
+func findUpdate(skill skills.Skill) (*updateInfo, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
 	}
-	repoPath := parts[1]
+	localCommit, remoteHead, branch, err := fetchSkillHeads(cm, git.NewManager(), skill)
+	if err != nil {
+		return nil, err
+	}
+	if remoteHead != "" && remoteHead != localCommit {
+		return &updateInfo{remoteHead: remoteHead, branch: branch}, nil
+	}
+	return nil, nil
+}
 
-	userRepo := repoPath
-	if skill.Path != "" && strings.HasSuffix(repoPath, skill.Path) {
-		userRepo = repoPath[:len(repoPath)-len(skill.Path)-1]
+// fetchSkillHeads fetches a GitHub skill's clone and returns the latest local
+// and remote commits touching the skill's path, and the remote branch compared.
+func fetchSkillHeads(cm *config.Manager, gitMgr *git.Manager, skill skills.Skill) (local, remote, branch string, err error) {
+	userRepo, ok := githubUserRepo(skill)
+	if !ok {
+		return "", "", "", newError(KindValidation, "invalid skill ID %s", skill.ID)
 	}
 
 	localRepoDir := cm.GetRepoPath(skill.ID)
-
-	subPath := "."
-	if skill.Path != "" {
-		subPath = skill.Path
-	}
-	localCommit, err := gitMgr.GetLocalPathCommitID(localRepoDir, subPath)
+	subPath := skillSubPath(skill)
+	local, err = gitMgr.GetLocalPathCommitID(localRepoDir, subPath)
 	if err != nil {
-		return nil
+		return "", "", "", gitError(err, "failed to read %s", skill.ID)
 	}
 
-	fmt.Println(tui.RenderInfo("Checking for updates..."))
 	if err := gitMgr.Fetch(localRepoDir); err != nil {
-		return nil
+		return "", "", "", gitError(err, "failed to fetch %s", skill.ID)
 	}
 
-	branch := gitMgr.GetDefaultBranch(userRepo)
-	remoteHead, err := gitMgr.GetRemotePathCommitID(localRepoDir, "origin/"+branch, subPath)
+	// Compare with the branch git pull would merge; ask GitHub only when the
+	// clone is not on a branch.
+	branch, err = gitMgr.CurrentBranch(localRepoDir)
+	if err != nil || branch == "HEAD" {
+		branch = gitMgr.GetDefaultBranch(userRepo)
+	}
+	remote, err = gitMgr.GetRemotePathCommitID(localRepoDir, "origin/"+branch, subPath)
 	if err != nil {
-		return nil
+		return "", "", "", gitError(err, "failed to read %s", skill.ID)
 	}
+	return local, remote, branch, nil
+}
 
-	if remoteHead != "" && remoteHead != localCommit {
-		return &updateInfo{remoteHead: remoteHead, branch: branch}
+// githubUserRepo returns the "user/repo" part of a GitHub skill's ID.
+func githubUserRepo(skill skills.Skill) (string, bool) {
+	parts := strings.SplitN(skill.ID, ":", 2)
+	if len(parts) < 2 {
+		return "", false
 	}
-	return nil
+	repoPath := parts[1]
+	if skill.Path != "" && strings.HasSuffix(repoPath, skill.Path) {
+		return repoPath[:len(repoPath)-len(skill.Path)-1], true
+	}
+	return repoPath, true
 }
 
-func doUpdate(skill skills.Skill, info updateInfo) {
-	cm, err := config.NewManager()
+// skillSubPath returns the path of a GitHub skill inside its clone.
+func skillSubPath(skill skills.Skill) string {
+	if skill.Path != "" {
+		return skill.Path
+	}
+	return "."
+}
+
+func doUpdate(skill skills.Skill, info updateInfo) error {
+	cm, err := openConfig()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return
+		return err
 	}
 	registry := skills.NewRegistry(cm)
 	gitMgr := git.NewManager()
 
-	fmt.Println(tui.RenderInfo("Updating " + skill.ID + "..."))
+	fmt.Fprintln(out, tui.RenderInfo("Updating "+skill.ID+"..."))
 	destPath := cm.GetRepoPath(skill.ID)
 
 	if err := gitMgr.PullQuiet(destPath); err != nil {
-		fmt.Println(tui.RenderError("Failed: " + err.Error()))
-		return
+		return gitError(err, "failed to pull")
 	}
 
 	registry.UpdateSkillVersion(skill.ID, info.remoteHead)
-	fmt.Println(tui.RenderSuccess("Updated " + skill.ID))
+	fmt.Fprintln(out, tui.RenderSuccess("Updated "+skill.ID))
+	return refreshCopies(nil, cm, gitMgr, skill.ID)
 }
 
 func doDelete(id string) {
 	cm, err := config.NewManager()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
+		fmt.Fprintln(out, tui.RenderError("Failed to initialize config: "+err.Error()))
 		return
 	}
 	registry := skills.NewRegistry(cm)
@@ -193,23 +451,39 @@ func doDelete(id string) {
 	var confirm bool
 	if err := huh.NewForm(huh.NewGroup(
 		huh.NewConfirm().
-			Title(fmt.Sprintf("Delete %s? This cannot be undone.", id)).
+			Title(fmt.Sprintf("Delete %s? agm undo can restore it.", id)).
 			Affirmative("Yes, delete").
 			Negative("Cancel").
 			Value(&confirm),
 	)).Run(); err != nil {
-		fmt.Println(tui.MutedText.Render("Cancelled."))
+		fmt.Fprintln(out, tui.MutedText.Render("Cancelled."))
 		return
 	}
 
 	if !confirm {
-		fmt.Println(tui.MutedText.Render("Cancelled."))
+		fmt.Fprintln(out, tui.MutedText.Render("Cancelled."))
 		return
 	}
 
-	os.RemoveAll(cm.GetRepoPath(id))
+	op := beginJournal(cm, "delete")
+	defer finishJournal(op)
+	if err := removeSkill(op, cm, registry, id); err != nil {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+	}
+}
+
+// removeSkill deletes a skill's repo directory and its registry entry.
+func removeSkill(op *journal.Op, cm *config.Manager, registry *skills.Registry, id string) error {
+	skill := registry.GetSkill(id)
+	if skill == nil {
+		return newError(KindNotFound, "skill %s not found", id)
+	}
+	if err := op.SkillRemoved(*skill, cm.GetRepoPath(id)); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", id, err)
+	}
 	registry.RemoveSkill(id)
-	fmt.Println(tui.RenderSuccess("Deleted " + id))
+	fmt.Fprintln(out, tui.RenderSuccess("Deleted "+id))
+	return nil
 }
 
 // --- helpers ---
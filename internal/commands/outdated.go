@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// OutdatedReport compares installed skills with their remotes.
+type OutdatedReport struct {
+	// Registry compares the commit of the last sync with the registry's origin.
+	Registry *OutdatedEntry  `json:"registry,omitempty"`
+	Skills   []OutdatedEntry `json:"skills"`
+	Outdated int             `json:"outdated"` // entries behind, including the registry
+	Failed   int             `json:"failed"`
+
+	errs []error
+}
+
+// OutdatedEntry is the update state of one skill, or of the registry clone.
+type OutdatedEntry struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Current string `json:"current,omitempty"`
+	Latest  string `json:"latest,omitempty"`
+	Behind  int    `json:"behind"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (r *OutdatedReport) add(e OutdatedEntry, err error) {
+	if err != nil {
+		e.Error = err.Error()
+		r.Failed++
+		r.errs = append(r.errs, err)
+	} else if e.Behind > 0 {
+		r.Outdated++
+	}
+	r.Skills = append(r.Skills, e)
+}
+
+// Outdated fetches every GitHub skill's clone and the registry clone and
+// reports how far each skill is behind. Nothing is pulled. It returns a
+// KindOutdated error when updates are available and no check failed.
+func Outdated() (*OutdatedReport, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	gitMgr := git.NewManager()
+	if err := gitMgr.CheckGitVersion(); err != nil {
+		return nil, classify(KindGit, err)
+	}
+
+	var githubSkills, registrySkills []skills.Skill
+	for _, skill := range skills.NewRegistry(cm).GetAllSkills() {
+		switch skill.Type {
+		case "github":
+			githubSkills = append(githubSkills, skill)
+		case "registry":
+			registrySkills = append(registrySkills, skill)
+		}
+	}
+
+	report := &OutdatedReport{Skills: []OutdatedEntry{}}
+	if len(githubSkills)+len(registrySkills) > 0 {
+		fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("Checking %d skill(s) for updates...", len(githubSkills)+len(registrySkills))))
+	}
+
+	for _, skill := range githubSkills {
+		entry := OutdatedEntry{ID: skill.ID, Type: skill.Type}
+		local, remote, branch, err := fetchSkillHeads(cm, gitMgr, skill)
+		if err == nil {
+			entry.Current, entry.Latest = local, remote
+			if local != remote {
+				entry.Behind, err = gitMgr.CountCommits(cm.GetRepoPath(skill.ID), local, "origin/"+branch, skillSubPath(skill))
+				if err != nil {
+					err = gitError(err, "failed to compare %s", skill.ID)
+				}
+			}
+		}
+		report.add(entry, err)
+	}
+
+	if len(registrySkills) > 0 {
+		checkRegistryOutdated(cm, gitMgr, registrySkills, report)
+	}
+
+	if report.Failed > 0 {
+		return report, aggregateError(report.errs, "%d skill(s) could not be checked", report.Failed)
+	}
+	if report.Outdated > 0 {
+		return report, newError(KindOutdated, "%d update(s) available", report.Outdated)
+	}
+	return report, nil
+}
+
+// checkRegistryOutdated fetches the registry clone and compares each registry
+// skill's recorded commit with the registry's origin.
+func checkRegistryOutdated(cm *config.Manager, gitMgr *git.Manager, registrySkills []skills.Skill, report *OutdatedReport) {
+	registryDir := cm.GetRegistryDir()
+	failAll := func(err error) {
+		for _, skill := range registrySkills {
+			report.add(OutdatedEntry{ID: skill.ID, Type: skill.Type, Current: skill.CommitID}, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(registryDir, ".git")); err != nil {
+		failAll(newError(KindNotFound, "registry clone %s is missing; run agm sync", registryDir))
+		return
+	}
+	if err := gitMgr.Fetch(registryDir); err != nil {
+		failAll(gitError(err, "failed to fetch the registry"))
+		return
+	}
+	branch, err := gitMgr.CurrentBranch(registryDir)
+	if err != nil {
+		failAll(gitError(err, "failed to read the registry clone"))
+		return
+	}
+	upstream := "origin/" + branch
+
+	synced, err := gitMgr.ResolveRef(registryDir, "HEAD")
+	if err == nil {
+		reg := &OutdatedEntry{ID: cm.GetRegistry(), Type: "registry", Current: synced}
+		if reg.Latest, err = gitMgr.ResolveRef(registryDir, upstream); err == nil {
+			reg.Behind, err = gitMgr.CountCommits(registryDir, synced, upstream, ".")
+		}
+		if err != nil {
+			reg.Error = err.Error()
+		} else if reg.Behind > 0 {
+			report.Outdated++
+		}
+		report.Registry = reg
+	}
+
+	scanPath := gitMgr.NormalizeURL(cm.GetRegistry()).Path
+	for _, skill := range registrySkills {
+		entry := OutdatedEntry{ID: skill.ID, Type: skill.Type, Current: skill.CommitID}
+		skillPath := path.Join(scanPath, cm.GetLinkName(skill.ID))
+		entry.Latest, err = gitMgr.GetRemotePathCommitID(registryDir, upstream, skillPath)
+		if err == nil && skill.CommitID != "" && entry.Latest != skill.CommitID {
+			entry.Behind, err = gitMgr.CountCommits(registryDir, skill.CommitID, upstream, skillPath)
+		}
+		if err != nil {
+			err = gitError(err, "failed to compare %s", skill.ID)
+		}
+		report.add(entry, err)
+	}
+}
+
+// Print writes the report as a table.
+func (r *OutdatedReport) Print() {
+	if len(r.Skills) == 0 {
+		fmt.Fprintln(out, tui.MutedText.Render("No GitHub or registry skills installed."))
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SKILL\tCURRENT\tLATEST\tBEHIND")
+	row := func(e OutdatedEntry) {
+		behind := fmt.Sprintf("%d", e.Behind)
+		if e.Error != "" {
+			behind = "error: " + e.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.ID, truncate(e.Current, 7), truncate(e.Latest, 7), behind)
+	}
+	if r.Registry != nil {
+		row(*r.Registry)
+	}
+	for _, e := range r.Skills {
+		row(e)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out)
+	switch {
+	case r.Outdated > 0:
+		fmt.Fprintln(out, tui.RenderWarning(fmt.Sprintf("%d update(s) available — run agm update for GitHub skills and agm sync for the registry", r.Outdated)))
+	case r.Failed == 0:
+		fmt.Fprintln(out, tui.RenderSuccess("All skills are up to date"))
+	}
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOutdatedComparesRegistryWithOrigin(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	t.Chdir(t.TempDir())
+	setGitIdentity(t)
+
+	work := t.TempDir()
+	mustRunGit(t, work, "init", "-q", "-b", "main")
+	mustMkdirAll(t, filepath.Join(work, "review"))
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v1")
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "v1")
+	remote := filepath.Join(t.TempDir(), "registry.git")
+	mustRunGit(t, work, "clone", "-q", "--bare", work, remote)
+
+	if _, err := Sync("file://" + remote); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	report, err := Outdated()
+	if err != nil {
+		t.Fatalf("Outdated() on a fresh sync failed: %v", err)
+	}
+	if report.Outdated != 0 || len(report.Skills) != 1 {
+		t.Fatalf("Outdated() = %+v, want one up-to-date skill", report)
+	}
+
+	mustWriteFile(t, filepath.Join(work, "review", "SKILL.md"), "v2")
+	mustRunGit(t, work, "commit", "-q", "-am", "v2")
+	mustWriteFile(t, filepath.Join(work, "README.md"), "readme")
+	mustRunGit(t, work, "add", "-A")
+	mustRunGit(t, work, "commit", "-q", "-m", "readme")
+	mustRunGit(t, work, "push", "-q", remote, "main")
+
+	report, err = Outdated()
+	if KindOf(err) != KindOutdated {
+		t.Fatalf("Outdated() error = %v, want kind outdated", err)
+	}
+	if report.Registry == nil || report.Registry.Behind != 2 {
+		t.Fatalf("registry = %+v, want 2 commits behind", report.Registry)
+	}
+	if s := report.Skills[0]; s.ID != "registry:review" || s.Behind != 1 || s.Latest == s.Current {
+		t.Fatalf("skill = %+v, want registry:review 1 commit behind", s)
+	}
+}
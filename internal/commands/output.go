@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"io"
+	"os"
+
+	"github.com/ArdentaCorp/agent-management/internal/git"
+)
+
+// out receives human-readable progress and result messages.
+var out io.Writer = os.Stdout
+
+// SetOutput redirects human-readable messages, e.g. to stderr when stdout
+// carries a JSON document.
+func SetOutput(w io.Writer) {
+	out = w
+}
+
+// ProgressEvent is one progress update of a long-running operation, such as a
+// clone or a registry sync. With -o json the CLI writes each event to stderr
+// as a one-line JSON document.
+type ProgressEvent struct {
+	Op      string `json:"op"`      // clone, pull, sync or update
+	Subject string `json:"subject"` // skill ID, or "registry"
+	Phase   string `json:"phase,omitempty"`
+	Percent int    `json:"percent"` // -1 when only a count is known
+	Current int    `json:"current,omitempty"`
+	Total   int    `json:"total,omitempty"`
+	Done    bool   `json:"done,omitempty"`
+}
+
+// progress receives progress events; nil discards them.
+var progress func(ProgressEvent)
+
+// SetProgress sets the receiver of progress events. fn may be called from
+// several goroutines, though never concurrently for the same operation.
+func SetProgress(fn func(ProgressEvent)) {
+	progress = fn
+}
+
+func reportProgress(e ProgressEvent) {
+	if progress != nil {
+		progress(e)
+	}
+}
+
+// trackGit returns a copy of gitMgr that reports its clone and pull progress
+// as op on subject, and a function that reports the operation done.
+func trackGit(gitMgr *git.Manager, op, subject string) (*git.Manager, func()) {
+	if progress == nil {
+		return gitMgr, func() {}
+	}
+	tracked := gitMgr.WithProgress(func(p git.Progress) {
+		reportProgress(ProgressEvent{Op: op, Subject: subject, Phase: p.Phase, Percent: p.Percent, Current: p.Current, Total: p.Total})
+	})
+	return tracked, func() {
+		reportProgress(ProgressEvent{Op: op, Subject: subject, Percent: 100, Done: true})
+	}
+}
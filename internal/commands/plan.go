@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// PlanAction is one change a mutating operation would make.
+// Op is one of set-registry, clone, fetched, add, update, unchanged, replace,
+// remove, delete, link, copy, unlink, conflict or gc. fetched records a fetch
+// the dry run itself made and conflict a path the operation would refuse to
+// touch; like unchanged, neither is a change.
+type PlanAction struct {
+	Op      string `json:"op"`
+	SkillID string `json:"skillId,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// Plan lists the changes an operation would make, computed without touching disk.
+type Plan struct {
+	Operation string       `json:"operation"`
+	Actions   []PlanAction `json:"actions"`
+}
+
+func newPlan(operation string) *Plan {
+	return &Plan{Operation: operation, Actions: []PlanAction{}}
+}
+
+func (p *Plan) add(op, skillID, path, detail string) {
+	p.Actions = append(p.Actions, PlanAction{Op: op, SkillID: skillID, Path: path, Detail: detail})
+}
+
+// Changes returns the number of actions that would modify something.
+func (p *Plan) Changes() int {
+	n := 0
+	for _, a := range p.Actions {
+		if a.Op != "unchanged" && a.Op != "fetched" && a.Op != "conflict" {
+			n++
+		}
+	}
+	return n
+}
+
+var planSymbols = map[string]string{
+	"set-registry": "*",
+	"clone":        "*",
+	"fetched":      "·",
+	"add":          "+",
+	"link":         "+",
+	"copy":         "+",
+	"conflict":     "!",
+	"update":       "↑",
+	"replace":      "~",
+	"remove":       "-",
+	"delete":       "-",
+	"unlink":       "-",
+	"gc":           "*",
+}
+
+// Print writes the plan in human-readable form.
+func (p *Plan) Print() {
+	fmt.Fprintln(out, tui.RenderInfo("Dry run: "+p.Operation+" would make these changes (nothing was changed)"))
+	unchanged := 0
+	for _, a := range p.Actions {
+		if a.Op == "unchanged" {
+			unchanged++
+			continue
+		}
+		subject := a.SkillID
+		if subject == "" {
+			subject = a.Path
+		}
+		line := fmt.Sprintf("  %s %-12s %s", planSymbols[a.Op], a.Op, subject)
+		if a.Detail != "" {
+			line += " " + tui.MutedText.Render("("+a.Detail+")")
+		}
+		if a.SkillID != "" && a.Path != "" {
+			line += "\n" + tui.MutedText.Render("                 "+a.Path)
+		}
+		fmt.Fprintln(out, line)
+	}
+	summary := fmt.Sprintf("%d change(s)", p.Changes())
+	if unchanged > 0 {
+		summary += fmt.Sprintf(", %d unchanged", unchanged)
+	}
+	fmt.Fprintln(out, tui.MutedText.Render(summary))
+}
+
+// PlanLink returns the links and copies Link would create, using the same
+// decisions as Link. Paths Link would refuse to touch are planned as
+// conflicts.
+func PlanLink(ids []string, projects []project.Info) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+	gitMgr := git.NewManager()
+
+	plan := newPlan("link")
+	for _, p := range projects {
+		for _, id := range ids {
+			skill := registry.GetSkill(id)
+			if skill == nil {
+				return nil, newError(KindNotFound, "skill %s not found", id)
+			}
+			linkPath := filepath.Join(p.SkillDir, cm.GetLinkName(id))
+			mode, err := toolLinkMode(p)
+			if err != nil {
+				return nil, err
+			}
+			var action string
+			if mode == config.LinkModeSymlink {
+				action, err = symlinkAction(cm, *skill, linkPath)
+			} else {
+				action, _, err = copyAction(cm, gitMgr, *skill, p.Root, mode, linkPath)
+			}
+			switch {
+			case KindOf(err) == KindConflict:
+				plan.add("conflict", id, linkPath, err.Error())
+			case err != nil:
+				return nil, err
+			case action == "edited":
+				plan.add("unchanged", id, linkPath, p.Type+": edited since agm copied it, left alone")
+			case action == "unchanged":
+				plan.add("unchanged", id, linkPath, p.Type)
+			case mode != config.LinkModeSymlink:
+				plan.add(action, id, linkPath, p.Type+" → "+mode+" of "+skillTargetPath(cm, *skill))
+			default:
+				plan.add(action, id, linkPath, p.Type+" → "+skillTargetPath(cm, *skill))
+			}
+		}
+	}
+	return plan, nil
+}
+
+// PlanUnlink returns the links Unlink would remove.
+func PlanUnlink(ids []string, projects []project.Info) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := newPlan("unlink")
+	for _, p := range projects {
+		for _, id := range ids {
+			linkPath := filepath.Join(p.SkillDir, cm.GetLinkName(id))
+			if _, err := os.Lstat(linkPath); err != nil {
+				plan.add("unchanged", id, linkPath, p.Type)
+				continue
+			}
+			plan.add("unlink", id, linkPath, p.Type)
+		}
+	}
+	return plan, nil
+}
+
+// PlanRemove returns the repo directories and registry entries Remove would delete.
+// Links in the given projects are not removed by Remove and are reported as such.
+func PlanRemove(ids []string, projects []project.Info) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+	allSkills := registry.GetAllSkills()
+
+	plan := newPlan("remove")
+	for _, id := range ids {
+		if registry.GetSkill(id) == nil {
+			return nil, newError(KindNotFound, "skill %s not found", id)
+		}
+		detail := ""
+		var linkedIn []string
+		for _, p := range projects {
+			if getLinkedSkills(allSkills, cm, p.SkillDir)[id] {
+				linkedIn = append(linkedIn, p.Type)
+			}
+		}
+		if len(linkedIn) > 0 {
+			detail = "links left dangling in " + strings.Join(linkedIn, ", ")
+		}
+		plan.add("delete", id, cm.GetRepoPath(id), detail)
+	}
+	return plan, nil
+}
+
+// PlanUpdate fetches each GitHub skill, at most jobs at a time, and returns
+// the pulls Update would make. Fetching only updates remote-tracking refs
+// inside the skill's clone.
+func PlanUpdate(ids []string, jobs int) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+
+	var list []skills.Skill
+	for _, id := range ids {
+		skill := registry.GetSkill(id)
+		if skill == nil {
+			return nil, newError(KindNotFound, "skill %s not found", id)
+		}
+		list = append(list, *skill)
+	}
+
+	fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("Checking %d skill(s) for updates...", len(list))))
+	gitMgr := git.NewManager()
+	heads := make([][2]string, len(list))
+	errs := make([]error, len(list))
+	runParallel(len(list), jobs, func(i int) {
+		if list[i].Type == "github" {
+			heads[i][0], heads[i][1], _, errs[i] = fetchSkillHeads(cm, gitMgr, list[i])
+		}
+	}, func(int) {})
+
+	plan := newPlan("update")
+	for i, skill := range list {
+		local, remote := heads[i][0], heads[i][1]
+		switch {
+		case errs[i] != nil:
+			return nil, errs[i]
+		case skill.Type != "github":
+			plan.add("unchanged", skill.ID, "", skill.Type)
+		case remote == "" || remote == local:
+			plan.add("unchanged", skill.ID, "", "up to date")
+		default:
+			plan.add("update", skill.ID, cm.GetRepoPath(skill.ID), truncate(skill.CommitID, 7)+" → "+truncate(remote, 7))
+		}
+	}
+	return plan, nil
+}
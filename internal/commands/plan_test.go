@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestPlanSyncSkills(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+
+	registry.AddSkill("registry:kept", "registry", "aaa", "")
+	registry.AddSkill("registry:changed", "registry", "bbb", "")
+	registry.AddSkill("registry:gone", "registry", "ccc", "")
+	registry.AddSkill("github:org/repo/skills/fresh", "github", "ddd", "skills/fresh")
+
+	projectDir := t.TempDir()
+	mustWriteFile(t, filepath.Join(projectDir, "gone"), "linked")
+	mustWriteFile(t, filepath.Join(projectDir, "fresh"), "linked")
+	// Sync leaves directories it did not copy alone, so the plan must too.
+	vendoredDir := t.TempDir()
+	mustMkdirAll(t, filepath.Join(vendoredDir, "gone"))
+	mustWriteFile(t, filepath.Join(vendoredDir, "gone", "SKILL.md"), "vendored")
+
+	plan := newPlan("sync")
+	planSyncSkills(plan, cm, registry, []registrySkill{
+		{name: "kept", commit: "aaa"},
+		{name: "changed", commit: "eee"},
+		{name: "fresh", commit: "fff"},
+	}, []project.Info{{Type: "claude", SkillDir: projectDir}, {Type: "cursor", SkillDir: vendoredDir}})
+
+	got := make(map[string]string)
+	for _, a := range plan.Actions {
+		got[a.Op+" "+a.SkillID] = a.Path
+	}
+	for _, want := range []string{
+		"unchanged registry:kept",
+		"update registry:changed",
+		"replace github:org/repo/skills/fresh",
+		"unlink github:org/repo/skills/fresh",
+		"add registry:fresh",
+		"remove registry:gone",
+		"unlink registry:gone",
+	} {
+		if _, ok := got[want]; !ok {
+			t.Errorf("plan is missing %q; got %v", want, plan.Actions)
+		}
+	}
+	if len(plan.Actions) != 7 {
+		t.Fatalf("plan has %d actions, want 7: %v", len(plan.Actions), plan.Actions)
+	}
+	if plan.Changes() != 6 {
+		t.Fatalf("Changes() = %d, want 6", plan.Changes())
+	}
+
+	// Planning must not touch disk.
+	if _, err := os.Lstat(filepath.Join(projectDir, "gone")); err != nil {
+		t.Fatalf("planning removed a link: %v", err)
+	}
+	if registry.GetSkill("registry:gone") == nil {
+		t.Fatal("planning removed a registry entry")
+	}
+}
+
+func TestPlanLinkMatchesLink(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := config.NewManager()
+	if err != nil {
+		t.Fatalf("NewManager() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	for _, name := range []string{"linked", "dangling", "file", "taken", "other", "fresh"} {
+		registry.AddSkill("local:"+name, "local", "", "")
+		mustMkdirAll(t, cm.GetRepoPath("local:"+name))
+		mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:"+name), "SKILL.md"), name)
+	}
+
+	skillDir := t.TempDir()
+	if err := os.Symlink(cm.GetRepoPath("local:linked"), filepath.Join(skillDir, "linked")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(tempHome, "gone"), filepath.Join(skillDir, "dangling")); err != nil {
+		t.Fatal(err)
+	}
+	mustWriteFile(t, filepath.Join(skillDir, "file"), "mine")
+	if err := os.Symlink(cm.GetRepoPath("local:other"), filepath.Join(skillDir, "taken")); err != nil {
+		t.Fatal(err)
+	}
+	copyDir := t.TempDir()
+
+	ids := []string{"local:linked", "local:dangling", "local:file", "local:taken", "local:fresh"}
+	plan, err := PlanLink(ids, []project.Info{
+		{Type: "claude", SkillDir: skillDir},
+		{Type: "cursor", SkillDir: copyDir, LinkMode: config.LinkModeCopy},
+	})
+	if err != nil {
+		t.Fatalf("PlanLink() failed: %v", err)
+	}
+
+	got := make(map[string]string)
+	for _, a := range plan.Actions {
+		got[a.Path] = a.Op
+	}
+	for path, want := range map[string]string{
+		filepath.Join(skillDir, "linked"):   "unchanged",
+		filepath.Join(skillDir, "dangling"): "replace",
+		filepath.Join(skillDir, "file"):     "conflict",
+		filepath.Join(skillDir, "taken"):    "conflict",
+		filepath.Join(skillDir, "fresh"):    "link",
+		filepath.Join(copyDir, "fresh"):     "copy",
+	} {
+		if got[path] != want {
+			t.Errorf("plan for %s = %q, want %q", path, got[path], want)
+		}
+	}
+
+	// Link agrees with the plan.
+	_, err = Link(ids, []project.Info{{Type: "claude", SkillDir: skillDir}})
+	if KindOf(err) != KindConflict {
+		t.Fatalf("Link() error = %v, want a conflict", err)
+	}
+	if target, _ := os.Readlink(filepath.Join(skillDir, "taken")); target != cm.GetRepoPath("local:other") {
+		t.Fatalf("Link() changed another skill's link to %s", target)
+	}
+	if _, err := os.Stat(filepath.Join(skillDir, "dangling")); err != nil {
+		t.Fatalf("Link() did not replace the dangling link: %v", err)
+	}
+}
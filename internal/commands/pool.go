@@ -0,0 +1,42 @@
+package commands
+
+import "sync"
+
+// DefaultJobs is how many skills are fetched at once by commands that talk to
+// many remotes.
+const DefaultJobs = 8
+
+// runParallel calls work(i) for i in [0, n) on at most jobs goroutines. done(i)
+// is called on the caller's goroutine as each item finishes, so it may touch
+// shared state such as skills.json or the output without locking.
+func runParallel(n, jobs int, work func(i int), done func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	jobs = min(jobs, n)
+
+	items := make(chan int)
+	finished := make(chan int)
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range items {
+				work(i)
+				finished <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range n {
+			items <- i
+		}
+		close(items)
+		wg.Wait()
+		close(finished)
+	}()
+	for i := range finished {
+		done(i)
+	}
+}
@@ -0,0 +1,277 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// Ways a project link can point at a skill in agm's repo.
+const (
+	LinkAbsolute = "absolute" // the skill's absolute path
+	LinkRelative = "relative" // a path relative to the link
+	LinkHome     = "home"     // a relative path through the project's HomeLinkName
+)
+
+// HomeLinkName is the link in a project root that points at agm's home
+// directory. Links made with the home strategy go through it, so moving agm's
+// home, or mounting it elsewhere, only needs this one link to change.
+const HomeLinkName = ".agm-home"
+
+// RelinkResult summarizes agm relink.
+type RelinkResult struct {
+	Project  string       `json:"project"`
+	Strategy string       `json:"strategy"`
+	Links    []LinkResult `json:"links"`
+	Failed   int          `json:"failed"`
+}
+
+// linkStrategy returns the project's link strategy, falling back to the
+// global setting.
+func linkStrategy(cm *config.Manager, m *manifest.Manifest) string {
+	if m.LinkStrategy != "" {
+		return m.LinkStrategy
+	}
+	if strategy := cm.GetLinkStrategy(); strategy != "" {
+		return strategy
+	}
+	return LinkAbsolute
+}
+
+// projectLinkStrategy returns the link strategy of the project at root; links
+// outside a project are absolute.
+func projectLinkStrategy(cm *config.Manager, root string) (string, error) {
+	if root == "" {
+		return LinkAbsolute, nil
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return "", wrapError(KindValidation, err, "failed to read manifest")
+	}
+	return checkLinkStrategy(linkStrategy(cm, m))
+}
+
+func checkLinkStrategy(strategy string) (string, error) {
+	switch strategy {
+	case LinkAbsolute:
+	case LinkRelative, LinkHome:
+		if runtime.GOOS == "windows" {
+			return "", newError(KindValidation, "the %s link strategy needs symlinks; Windows links are directory junctions, which are always absolute", strategy)
+		}
+	default:
+		return "", newError(KindValidation, "unknown link strategy %q (expected absolute, relative or home)", strategy)
+	}
+	return strategy, nil
+}
+
+// linkTarget returns what a link at linkPath in the project at root points to
+// under strategy, for a skill at the absolute path target.
+func linkTarget(cm *config.Manager, strategy, root, linkPath, target string) (string, error) {
+	switch strategy {
+	case LinkRelative:
+		return filepath.Rel(filepath.Dir(linkPath), target)
+	case LinkHome:
+		rel, err := filepath.Rel(cm.GetHomeDir(), target)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Rel(filepath.Dir(linkPath), filepath.Join(root, HomeLinkName, rel))
+	}
+	return target, nil
+}
+
+// ensureHomeLink points the project's HomeLinkName at agm's home directory.
+func ensureHomeLink(op *journal.Op, cm *config.Manager, root string) error {
+	linkPath := filepath.Join(root, HomeLinkName)
+	home := cm.GetHomeDir()
+	info, err := os.Lstat(linkPath)
+	if err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return newError(KindConflict, "%s exists and is not a link", linkPath)
+		}
+		current, _ := os.Readlink(linkPath)
+		if current == home {
+			return nil
+		}
+		if err := os.Remove(linkPath); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", linkPath, err)
+		}
+		op.LinkRemoved("", linkPath, current)
+	}
+	if err := createLink(home, linkPath); err != nil {
+		return fmt.Errorf("failed to link %s: %w", linkPath, err)
+	}
+	op.LinkCreated("", linkPath, home)
+	return nil
+}
+
+// agmPath maps a link target that goes through a project's HomeLinkName, or
+// into an agm home on another machine, onto this agm home. Other paths are
+// returned unchanged.
+func agmPath(cm *config.Manager, target string) string {
+	sep := string(filepath.Separator)
+	for _, dir := range []string{HomeLinkName, config.DirName} {
+		if i := strings.LastIndex(target, sep+dir+sep); i >= 0 {
+			return filepath.Join(cm.GetHomeDir(), target[i+len(dir)+2:])
+		}
+	}
+	return target
+}
+
+// Relink rewrites the links to agm's skills in the detected tools of the
+// project in dir (the current directory if empty) to use strategy, saving it
+// as the project's link strategy. An empty strategy keeps the project's
+// current one, which also repairs links made on another machine.
+func Relink(dir, strategy string) (*RelinkResult, error) {
+	explicit := strategy != ""
+	root, cm, strategy, err := openRelink(dir, strategy)
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	if explicit && m.LinkStrategy != strategy {
+		if m, err = loadSettingsManifest(cm, root); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", manifest.FileName, err)
+		}
+		m.LinkStrategy = strategy
+		if err := m.Save(); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", manifest.FileName, err)
+		}
+	}
+
+	op := beginJournal(cm, "relink")
+	defer finishJournal(op)
+	if strategy == LinkHome {
+		if err := ensureHomeLink(op, cm, root); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &RelinkResult{Project: root, Strategy: strategy, Links: []LinkResult{}}
+	var errs []error
+	relinked := 0
+	for _, p := range project.NewDetector(root).DetectAll() {
+		for _, l := range scanRelink(cm, strategy, root, p) {
+			link := LinkResult{SkillID: l.skillID, Tool: p.Type, Path: l.path, Status: "unchanged"}
+			if l.target != l.current {
+				link.Status = "relinked"
+				err := os.Remove(l.path)
+				if err == nil {
+					op.LinkRemoved(l.skillID, l.path, l.current)
+					err = createLink(l.target, l.path)
+				}
+				if err != nil {
+					err = fmt.Errorf("failed to relink %s: %w", l.path, err)
+					link.Status, link.Error = "failed", err.Error()
+					fmt.Fprintln(out, tui.RenderError(err.Error()))
+					errs = append(errs, err)
+					result.Failed++
+				} else {
+					op.LinkCreated(l.skillID, l.path, l.target)
+					fmt.Fprintln(out, tui.RenderSuccess("Relinked "+l.skillID+" in "+p.Type+" "+tui.MutedText.Render("→ "+l.target)))
+					relinked++
+				}
+			}
+			result.Links = append(result.Links, link)
+		}
+	}
+	if strategy != LinkHome {
+		if current, err := os.Readlink(filepath.Join(root, HomeLinkName)); err == nil {
+			if err := os.Remove(filepath.Join(root, HomeLinkName)); err == nil {
+				op.LinkRemoved("", filepath.Join(root, HomeLinkName), current)
+			}
+		}
+	}
+	refreshIgnoreBlock(cm, root)
+
+	summary := fmt.Sprintf("Relink complete: %d link(s) now %s, %d unchanged", relinked, strategy, len(result.Links)-relinked-result.Failed)
+	if len(errs) > 0 {
+		fmt.Fprintln(out, tui.RenderWarning(summary+fmt.Sprintf(", %d failed", result.Failed)))
+		return result, aggregateError(errs, "%d link(s) could not be rewritten", result.Failed)
+	}
+	fmt.Fprintln(out, tui.RenderSuccess(summary))
+	return result, nil
+}
+
+// PlanRelink returns the links Relink would rewrite.
+func PlanRelink(dir, strategy string) (*Plan, error) {
+	root, cm, strategy, err := openRelink(dir, strategy)
+	if err != nil {
+		return nil, err
+	}
+	plan := newPlan("relink")
+	if strategy == LinkHome {
+		if current, _ := os.Readlink(filepath.Join(root, HomeLinkName)); current != cm.GetHomeDir() {
+			plan.add("link", "", filepath.Join(root, HomeLinkName), cm.GetHomeDir())
+		}
+	}
+	for _, p := range project.NewDetector(root).DetectAll() {
+		for _, l := range scanRelink(cm, strategy, root, p) {
+			if l.target == l.current {
+				plan.add("unchanged", l.skillID, l.path, p.Type)
+			} else {
+				plan.add("replace", l.skillID, l.path, p.Type+", → "+l.target)
+			}
+		}
+	}
+	return plan, nil
+}
+
+func openRelink(dir, strategy string) (string, *config.Manager, string, error) {
+	root, err := projectRoot(dir)
+	if err != nil {
+		return "", nil, "", err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return "", nil, "", err
+	}
+	if strategy == "" {
+		strategy, err = projectLinkStrategy(cm, root)
+	} else {
+		strategy, err = checkLinkStrategy(strategy)
+	}
+	return root, cm, strategy, err
+}
+
+// relinkItem is one link Relink rewrites, or leaves alone when its target is
+// already right.
+type relinkItem struct {
+	skillID, path, current, target string
+}
+
+// scanRelink returns the links to agm's skills in p's skill directory with
+// the targets strategy gives them.
+func scanRelink(cm *config.Manager, strategy, root string, p project.Info) []relinkItem {
+	var items []relinkItem
+	entries, _ := os.ReadDir(p.SkillDir)
+	for _, entry := range entries {
+		path := filepath.Join(p.SkillDir, entry.Name())
+		current, err := os.Readlink(path)
+		if err != nil {
+			continue
+		}
+		resolved, _ := readLinkTarget(path)
+		skillID := linkSkillID(cm, resolved)
+		if skillID == "" {
+			continue
+		}
+		target, err := linkTarget(cm, strategy, root, path, agmPath(cm, resolved))
+		if err != nil {
+			continue
+		}
+		items = append(items, relinkItem{skillID: skillID, path: path, current: current, target: target})
+	}
+	return items
+}
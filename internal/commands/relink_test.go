@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRelinkSwitchesLinkStrategies(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relative links need symlinks")
+	}
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	mustMkdirAll(t, filepath.Join(src, "notes"))
+	mustWriteFile(t, filepath.Join(src, "notes", "SKILL.md"), "notes")
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:notes"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	linkPath := filepath.Join(projects[0].SkillDir, "notes")
+
+	result, err := Relink("", LinkRelative)
+	if err != nil || len(result.Links) != 1 || result.Links[0].Status != "relinked" {
+		t.Fatalf("Relink(relative) = %+v, %v", result, err)
+	}
+	if target, _ := os.Readlink(linkPath); filepath.IsAbs(target) {
+		t.Fatalf("relative link points to %s", target)
+	}
+	assertFileContent(t, filepath.Join(linkPath, "SKILL.md"), "notes")
+
+	if _, err := Relink("", LinkHome); err != nil {
+		t.Fatalf("Relink(home) failed: %v", err)
+	}
+	if target, _ := os.Readlink(linkPath); target != filepath.Join("..", "..", HomeLinkName, "repo", "local__notes") {
+		t.Fatalf("home link points to %s", target)
+	}
+	assertFileContent(t, filepath.Join(linkPath, "SKILL.md"), "notes")
+	// New links follow the saved strategy, and status sees them as current.
+	if _, err := Link([]string{"local:notes"}, projects); err != nil {
+		t.Fatalf("Link() again failed: %v", err)
+	}
+	if report, _ := Status(""); !report.Clean {
+		t.Fatalf("status after relinking is not clean: %+v", report.Tools)
+	}
+
+	if _, err := Relink("", LinkAbsolute); err != nil {
+		t.Fatalf("Relink(absolute) failed: %v", err)
+	}
+	if target, _ := os.Readlink(linkPath); !filepath.IsAbs(target) {
+		t.Fatalf("absolute link points to %s", target)
+	}
+	if _, err := os.Lstat(filepath.Join(proj, HomeLinkName)); !os.IsNotExist(err) {
+		t.Fatalf("%s left behind after relinking absolute", HomeLinkName)
+	}
+
+	if _, err := Relink("", "bogus"); KindOf(err) != KindValidation {
+		t.Fatalf("Relink(bogus) = %v, want a validation error", err)
+	}
+}
@@ -0,0 +1,398 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// States of an entry in a tool's skill directory.
+const (
+	StateCurrent    = "current"    // linked to an installed skill that is up to date
+	StateOutdated   = "outdated"   // linked, but behind agm.lock or its remote
+	StateModified   = "modified"   // linked, but its files differ from agm.lock
+	StateUndeclared = "undeclared" // linked, but not listed in .agm.json
+	StateBroken     = "broken"     // a link whose target is gone or unregistered
+	StateUnmanaged  = "unmanaged"  // a skill agm did not link or copy
+	StateCollision  = "collision"  // a name claimed by more than one skill
+	StateMissing    = "missing"    // declared in .agm.json but not linked
+)
+
+// StatusReport compares what is in each detected tool's skill directory with
+// agm's repo and the project's manifest.
+type StatusReport struct {
+	Project  string       `json:"project"`
+	Manifest bool         `json:"manifest"` // whether the project has .agm.json
+	Lock     bool         `json:"lock"`     // whether the project has agm.lock
+	Tools    []ToolStatus `json:"tools"`
+	Clean    bool         `json:"clean"`
+}
+
+// ToolStatus lists the entries of one tool's skill directory.
+type ToolStatus struct {
+	Tool     string        `json:"tool"`
+	SkillDir string        `json:"skillDir"`
+	Entries  []StatusEntry `json:"entries"`
+}
+
+// StatusEntry is one skill name in a tool's skill directory.
+type StatusEntry struct {
+	Name    string `json:"name"`
+	SkillID string `json:"skillId,omitempty"`
+	State   string `json:"state"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// statusScan holds what Status compares the skill directories against.
+type statusScan struct {
+	cm         *config.Manager
+	gitMgr     *git.Manager
+	registry   *skills.Registry
+	manifest   *manifest.Manifest
+	lock       *manifest.Lock
+	byLinkName map[string][]string
+	versions   map[string]StatusEntry // version state per skill ID
+}
+
+// Status reports the skills in each AI tool detected in dir (the current
+// directory if empty). It runs offline: remotes are compared as of the last
+// fetch, e.g. by agm outdated.
+func Status(dir string) (*StatusReport, error) {
+	root, err := projectRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	m, err := manifest.Load(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	lock, err := manifest.LoadLock(root)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read lockfile")
+	}
+
+	s := &statusScan{
+		cm:         cm,
+		gitMgr:     git.NewManager(),
+		registry:   skills.NewRegistry(cm),
+		manifest:   m,
+		lock:       lock,
+		byLinkName: make(map[string][]string),
+		versions:   make(map[string]StatusEntry),
+	}
+	for _, skill := range s.registry.GetAllSkills() {
+		name := cm.GetLinkName(skill.ID)
+		s.byLinkName[name] = append(s.byLinkName[name], skill.ID)
+	}
+
+	report := &StatusReport{Project: root, Manifest: m.Exists(), Lock: lock.Exists(), Tools: []ToolStatus{}, Clean: true}
+	for _, p := range project.NewDetector(root).DetectAll() {
+		tool := s.tool(p)
+		for _, e := range tool.Entries {
+			if needsAttention(tool, e) {
+				report.Clean = false
+			}
+		}
+		report.Tools = append(report.Tools, tool)
+	}
+	return report, nil
+}
+
+func (s *statusScan) tool(p project.Info) ToolStatus {
+	tool := ToolStatus{Tool: p.Type, SkillDir: p.SkillDir, Entries: []StatusEntry{}}
+	declared := make(map[string]string) // link name → declared skill ID
+	for _, d := range s.manifest.Skills {
+		if slices.Contains(d.Tools, p.Type) {
+			declared[s.cm.GetLinkName(d.ID)] = d.ID
+		}
+	}
+
+	dirEntries, _ := os.ReadDir(p.SkillDir)
+	seen := make(map[string]bool)
+	for _, de := range dirEntries {
+		name := de.Name()
+		entryPath := filepath.Join(p.SkillDir, name)
+		info, err := os.Lstat(entryPath)
+		if _, staging := git.StagingPID(name); err != nil || staging {
+			continue
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			if !info.IsDir() {
+				continue
+			}
+			if prov, _ := manifest.ReadProvenance(entryPath); prov != nil {
+				seen[name] = true
+				tool.Entries = append(tool.Entries, s.copy(name, entryPath, *prov, declared))
+				continue
+			}
+			if !pathExists(filepath.Join(entryPath, "SKILL.md")) {
+				continue
+			}
+			seen[name] = true
+			e := StatusEntry{Name: name, State: StateUnmanaged}
+			if id, ok := declared[name]; ok {
+				e.State, e.SkillID, e.Detail = StateCollision, id, "a directory is in the way of declared "+id
+			} else if ids := s.byLinkName[name]; len(ids) > 0 {
+				e.State, e.Detail = StateCollision, "a directory is in the way of "+strings.Join(ids, ", ")
+			}
+			tool.Entries = append(tool.Entries, e)
+			continue
+		}
+
+		seen[name] = true
+		tool.Entries = append(tool.Entries, s.link(name, entryPath, declared))
+	}
+
+	for name, id := range declared {
+		if !seen[name] {
+			tool.Entries = append(tool.Entries, StatusEntry{Name: name, SkillID: id, State: StateMissing, Detail: "declared in " + manifest.FileName})
+		}
+	}
+	sort.Slice(tool.Entries, func(i, j int) bool { return tool.Entries[i].Name < tool.Entries[j].Name })
+	return tool
+}
+
+// link returns the state of the symlink named name.
+func (s *statusScan) link(name, linkPath string, declared map[string]string) StatusEntry {
+	e := StatusEntry{Name: name}
+	target, err := readLinkTarget(linkPath)
+	if err != nil {
+		e.State, e.Detail = StateBroken, err.Error()
+		return e
+	}
+	e.SkillID = linkSkillID(s.cm, target)
+	switch {
+	case e.SkillID == "":
+		if !pathExists(linkPath) {
+			e.State, e.Detail = StateBroken, "target "+target+" is missing"
+		} else {
+			e.State, e.Detail = StateUnmanaged, "link to "+target
+		}
+		return e
+	case !pathExists(linkPath):
+		e.State, e.Detail = StateBroken, "target "+target+" is missing"
+		return e
+	}
+	skill := s.registry.GetSkill(e.SkillID)
+	if skill == nil {
+		e.State, e.Detail = StateBroken, e.SkillID+" is not installed"
+		return e
+	}
+	if id, ok := declared[name]; ok && id != e.SkillID {
+		e.State, e.Detail = StateCollision, "linked to "+e.SkillID+" but "+manifest.FileName+" declares "+id
+		return e
+	}
+	if ids := s.byLinkName[name]; len(ids) > 1 {
+		e.State, e.Detail = StateCollision, "name shared by "+strings.Join(ids, ", ")
+		return e
+	}
+
+	version := s.version(*skill)
+	e.State, e.Detail = version.State, version.Detail
+	if e.State == StateCurrent && s.manifest.Exists() {
+		if _, ok := declared[name]; !ok {
+			e.State, e.Detail = StateUndeclared, "not in "+manifest.FileName
+		}
+	}
+	return e
+}
+
+// copy returns the state of a skill copy made by agm vendor or for a copy or
+// hardlink tool. Vendored copies are compared with agm.lock, not with the
+// installed skill, which may be missing; other copies with both.
+func (s *statusScan) copy(name, dir string, prov manifest.Provenance, declared map[string]string) StatusEntry {
+	made, edited, fix := "vendored", "vendored copy edited since agm vendor", "agm vendor --update"
+	if prov.LinkMode == config.LinkModeHardlink {
+		made, edited, fix = "hardlinked", "copy edited since agm made it", "agm install"
+	} else if prov.LinkMode != "" {
+		made, edited, fix = "copied", "copy edited since agm made it", "agm install"
+	}
+	e := StatusEntry{Name: name, SkillID: prov.ID, State: StateCurrent, Detail: made}
+	hash, err := manifest.HashDir(dir)
+	locked := s.lock.Get(prov.ID)
+	var installed string
+	if prov.LinkMode != "" && locked == nil {
+		installed = s.installedHash(prov.ID)
+	}
+	switch id, ok := declared[name]; {
+	case err != nil || hash != prov.Hash:
+		e.State, e.Detail = StateModified, edited
+	case ok && id != prov.ID:
+		e.State, e.Detail = StateCollision, "copy of "+prov.ID+" but "+manifest.FileName+" declares "+id
+	case locked != nil && locked.Hash != prov.Hash:
+		e.State, e.Detail = StateOutdated, made+" copy differs from "+manifest.LockFileName+"; run "+fix
+	case installed != "" && installed != prov.Hash:
+		e.State, e.Detail = StateOutdated, "copy differs from the installed skill; run "+fix
+	case !ok && s.manifest.Exists():
+		e.State, e.Detail = StateUndeclared, made+", not in "+manifest.FileName
+	}
+	return e
+}
+
+// installedHash returns the HashDir of the installed skill id, or "" when it
+// is not installed.
+func (s *statusScan) installedHash(id string) string {
+	skill := s.registry.GetSkill(id)
+	if skill == nil {
+		return ""
+	}
+	hash, _ := manifest.HashDir(skillTargetPath(s.cm, *skill))
+	return hash
+}
+
+// version compares an installed skill with agm.lock, or with its remote as of
+// the last fetch when it is not locked.
+func (s *statusScan) version(skill skills.Skill) StatusEntry {
+	if v, ok := s.versions[skill.ID]; ok {
+		return v
+	}
+	v := StatusEntry{State: StateCurrent}
+	if locked := s.lock.Get(skill.ID); locked != nil {
+		current, _ := lockEntry(s.cm, s.gitMgr, skill, "")
+		switch {
+		case locked.Commit != "" && current.Commit != locked.Commit:
+			v.State, v.Detail = StateOutdated, fmt.Sprintf("installed %s, locked %s", truncate(current.Commit, 7), truncate(locked.Commit, 7))
+		case current.Hash != locked.Hash:
+			v.State, v.Detail = StateModified, "files differ from "+manifest.LockFileName
+		}
+	} else if behind, branch := s.behind(skill); behind > 0 {
+		v.State, v.Detail = StateOutdated, fmt.Sprintf("%d commit(s) behind origin/%s", behind, branch)
+	}
+	s.versions[skill.ID] = v
+	return v
+}
+
+// behind counts the commits touching a skill that its remote has and the
+// installed copy lacks, as of the last fetch.
+func (s *statusScan) behind(skill skills.Skill) (int, string) {
+	var repoDir, subPath, from string
+	switch skill.Type {
+	case "github":
+		repoDir, subPath, from = s.cm.GetRepoPath(skill.ID), skillSubPath(skill), "HEAD"
+	case "registry":
+		repoDir, from = s.cm.GetRegistryDir(), skill.CommitID
+		subPath = path.Join(s.gitMgr.NormalizeURL(s.cm.GetRegistry()).Path, s.cm.GetLinkName(skill.ID))
+	default:
+		return 0, ""
+	}
+	branch, err := s.gitMgr.CurrentBranch(repoDir)
+	if err != nil || branch == "HEAD" || from == "" {
+		return 0, ""
+	}
+	n, err := s.gitMgr.CountCommits(repoDir, from, "origin/"+branch, subPath)
+	if err != nil {
+		return 0, ""
+	}
+	return n, branch
+}
+
+// readLinkTarget returns the absolute path a symlink points to.
+func readLinkTarget(linkPath string) (string, error) {
+	target, err := os.Readlink(linkPath)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(linkPath), target)
+	}
+	return filepath.Clean(target), nil
+}
+
+var stateSymbols = map[string]string{
+	StateCurrent:    tui.SuccessText.Render("✓"),
+	StateOutdated:   tui.WarningText.Render("↑"),
+	StateModified:   tui.WarningText.Render("M"),
+	StateUndeclared: tui.WarningText.Render("+"),
+	StateBroken:     tui.ErrorText.Render("✗"),
+	StateUnmanaged:  tui.MutedText.Render("?"),
+	StateCollision:  tui.ErrorText.Render("!"),
+	StateMissing:    tui.ErrorText.Render("-"),
+}
+
+// Print writes the report like git status.
+func (r *StatusReport) Print() {
+	if len(r.Tools) == 0 {
+		fmt.Fprintln(out, tui.RenderWarning("No AI tools detected in "+r.Project))
+		return
+	}
+	for i, t := range r.Tools {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintln(out, tui.RenderInfo(t.Tool+" "+tui.MutedText.Render(t.SkillDir)))
+		if len(t.Entries) == 0 {
+			fmt.Fprintln(out, tui.MutedText.Render("  no skills"))
+			continue
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, e := range t.Entries {
+			detail := e.SkillID
+			if e.Detail != "" {
+				if detail != "" {
+					detail += ": "
+				}
+				detail += e.Detail
+			}
+			fmt.Fprintf(w, "  %s %s\t%s\t%s\n", stateSymbols[e.State], e.Name, e.State, tui.MutedText.Render(detail))
+		}
+		w.Flush()
+	}
+	fmt.Fprintln(out)
+	if r.Clean {
+		fmt.Fprintln(out, tui.RenderSuccess("Every skill is linked and current"))
+	} else {
+		fmt.Fprintln(out, tui.RenderWarning(fmt.Sprintf("%d skill(s) need attention", r.attention())))
+	}
+	if !r.Manifest {
+		fmt.Fprintln(out, tui.MutedText.Render("No "+manifest.FileName+" in this project; link skills to create one."))
+	}
+}
+
+// attention counts the entries that are not current.
+func (r *StatusReport) attention() int {
+	n := 0
+	for _, t := range r.Tools {
+		for _, e := range t.Entries {
+			if needsAttention(t, e) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// needsAttention reports whether e keeps the project from being clean. The
+// user's own skill directories do not, but links agm did not make do, as in
+// Verify.
+func needsAttention(t ToolStatus, e StatusEntry) bool {
+	switch e.State {
+	case StateCurrent:
+		return false
+	case StateUnmanaged:
+		return isUnmanagedLink(t, e)
+	}
+	return true
+}
+
+// isUnmanagedLink reports whether the unmanaged entry e is a link rather than
+// a directory of the user's.
+func isUnmanagedLink(t ToolStatus, e StatusEntry) bool {
+	info, err := os.Lstat(filepath.Join(t.SkillDir, e.Name))
+	return err == nil && info.Mode()&os.ModeSymlink != 0
+}
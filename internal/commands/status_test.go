@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+)
+
+func TestStatusReportsDrift(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	for _, name := range []string{"kept", "edited", "dropped"} {
+		mustMkdirAll(t, filepath.Join(src, name))
+		mustWriteFile(t, filepath.Join(src, name, "SKILL.md"), name)
+	}
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:kept", "local:edited", "local:dropped"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	skillDir := projects[0].SkillDir
+	cm, _ := openConfig()
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:edited"), "SKILL.md"), "changed")
+	os.Remove(filepath.Join(skillDir, "dropped"))
+	if err := os.Symlink(filepath.Join(cm.GetRepoDir(), "local__gone"), filepath.Join(skillDir, "gone")); err != nil {
+		t.Fatal(err)
+	}
+	mustMkdirAll(t, filepath.Join(skillDir, "handmade"))
+	mustWriteFile(t, filepath.Join(skillDir, "handmade", "SKILL.md"), "mine")
+
+	report, err := Status("")
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if report.Clean || !report.Manifest || len(report.Tools) != 1 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	got := make(map[string]string)
+	for _, e := range report.Tools[0].Entries {
+		got[e.Name] = e.State
+	}
+	want := map[string]string{
+		"kept":     StateCurrent,
+		"edited":   StateModified,
+		"dropped":  StateMissing,
+		"gone":     StateBroken,
+		"handmade": StateUnmanaged,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for name, state := range want {
+		if got[name] != state {
+			t.Errorf("%s is %q, want %q", name, got[name], state)
+		}
+	}
+
+	// Linking the directory's name as well makes it a collision.
+	m, _ := manifest.Load(proj)
+	m.Link("local:handmade", "", "claude")
+	m.Save()
+	report, _ = Status("")
+	for _, e := range report.Tools[0].Entries {
+		if e.Name == "handmade" && e.State != StateCollision {
+			t.Errorf("handmade is %q once declared, want collision", e.State)
+		}
+	}
+}
+
+func TestStatusIgnoresTheUsersOwnSkills(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	mustMkdirAll(t, filepath.Join(src, "kept"))
+	mustWriteFile(t, filepath.Join(src, "kept", "SKILL.md"), "---\nname: kept\ndescription: Kept.\n---\n")
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:kept"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	skillDir := projects[0].SkillDir
+	mustMkdirAll(t, filepath.Join(skillDir, "handmade"))
+	mustWriteFile(t, filepath.Join(skillDir, "handmade", "SKILL.md"), "mine")
+
+	report, err := Status("")
+	if err != nil {
+		t.Fatalf("Status() failed: %v", err)
+	}
+	if !report.Clean {
+		t.Fatalf("a project with only the user's own skill besides agm's is not clean: %+v", report.Tools)
+	}
+	if _, err := Verify(""); err != nil {
+		t.Fatalf("Verify() disagrees with Status(): %v", err)
+	}
+
+	// A link agm did not make is drift for both.
+	if err := os.Symlink(t.TempDir(), filepath.Join(skillDir, "elsewhere")); err != nil {
+		t.Fatal(err)
+	}
+	if report, _ := Status(""); report.Clean {
+		t.Fatal("a foreign link left the project clean")
+	}
+	if _, err := Verify(""); KindOf(err) != KindDrift {
+		t.Fatalf("Verify() error = %v, want drift", err)
+	}
+}
@@ -3,11 +3,13 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
 	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
 	"github.com/ArdentaCorp/agent-management/internal/project"
 	"github.com/ArdentaCorp/agent-management/internal/skills"
 	"github.com/ArdentaCorp/agent-management/internal/tui"
@@ -18,47 +20,98 @@ import (
 // If interactive is true, prompts for the URL when not configured.
 // If interactive is false (--sync flag), fails if no registry is configured.
 func SyncSkills(interactive bool) {
-	cm, err := config.NewManager()
+	var registryURL string
+	if interactive {
+		cm, err := config.NewManager()
+		if err != nil {
+			fmt.Fprintln(out, tui.RenderError("Failed to initialize config: "+err.Error()))
+			return
+		}
+
+		if cm.GetRegistry() == "" {
+			// First time — ask for the URL
+			var inputURL string
+			form := huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Title("Registry URL").
+						Description("GitHub repo containing your team's skills").
+						Placeholder("https://github.com/org/skills").
+						Value(&inputURL),
+				),
+			)
+			if err := form.Run(); err != nil || strings.TrimSpace(inputURL) == "" {
+				return
+			}
+			registryURL = inputURL
+		}
+	}
+
+	if _, err := Sync(registryURL); err != nil {
+		fmt.Fprintln(out, tui.RenderError(err.Error()))
+	}
+}
+
+// SyncResult summarizes a registry sync.
+type SyncResult struct {
+	Registry      string           `json:"registry"`
+	Added         int              `json:"added"`
+	Updated       int              `json:"updated"`
+	Unchanged     int              `json:"unchanged"`
+	Replaced      int              `json:"replaced"`
+	ReplacedLinks int              `json:"replacedLinks"`
+	Removed       int              `json:"removed"`
+	RemovedLinks  int              `json:"removedLinks"`
+	Failed        int              `json:"failed"`
+	Skills        []SyncSkillEntry `json:"skills"`
+
+	errs []error
+}
+
+// SyncSkillEntry records what a sync did to one registry skill.
+// Action is one of added, updated, unchanged, removed or failed.
+type SyncSkillEntry struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Commit string `json:"commit,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r *SyncResult) fail(id string, err error) {
+	r.Failed++
+	r.errs = append(r.errs, err)
+	r.Skills = append(r.Skills, SyncSkillEntry{ID: id, Action: "failed", Error: err.Error()})
+}
+
+// Sync syncs all skills from the registry repo.
+// A non-empty registryURL is saved as the registry before syncing;
+// otherwise the configured registry is used.
+func Sync(registryURL string) (*SyncResult, error) {
+	cm, err := openConfig()
 	if err != nil {
-		fmt.Println(tui.RenderError("Failed to initialize config: " + err.Error()))
-		return
+		return nil, err
 	}
 	gitMgr := git.NewManager()
 
 	if err := gitMgr.CheckGitVersion(); err != nil {
-		fmt.Println(tui.RenderError(err.Error()))
-		return
+		return nil, classify(KindGit, err)
 	}
 
-	registryURL := cm.GetRegistry()
-
-	if registryURL == "" {
-		if !interactive {
-			fmt.Println(tui.RenderError("No registry configured. Run agm and use 'Sync skills' to set one up."))
-			return
-		}
-
-		// First time — ask for the URL
-		var inputURL string
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().
-					Title("Registry URL").
-					Description("GitHub repo containing your team's skills").
-					Placeholder("https://github.com/org/skills").
-					Value(&inputURL),
-			),
-		)
-		if err := form.Run(); err != nil || strings.TrimSpace(inputURL) == "" {
-			return
+	registryURL = strings.TrimSpace(registryURL)
+	if current := cm.GetRegistry(); registryURL != "" && registryURL != current {
+		if current != "" {
+			// The old clone tracks a different remote; start fresh.
+			os.RemoveAll(cm.GetRegistryDir())
 		}
-
-		registryURL = strings.TrimSpace(inputURL)
 		if err := cm.SetRegistry(registryURL); err != nil {
-			fmt.Println(tui.RenderError("Failed to save registry URL: " + err.Error()))
-			return
+			return nil, wrapError(KindConfig, err, "failed to save registry URL")
 		}
-		fmt.Println(tui.RenderSuccess("Registry saved: " + registryURL))
+		fmt.Fprintln(out, tui.RenderSuccess("Registry saved: "+registryURL))
+	}
+
+	registryURL = cm.GetRegistry()
+	if registryURL == "" {
+		return nil, newError(KindConfig, "no registry configured. Run agm sync --registry <url> or use 'Sync skills' in interactive mode")
 	}
 
 	registryDir := cm.GetRegistryDir()
@@ -70,18 +123,22 @@ func SyncSkills(interactive bool) {
 	// Clone or pull
 	if _, err := os.Stat(filepath.Join(registryDir, ".git")); os.IsNotExist(err) {
 		// First time — clone
-		fmt.Println(tui.RenderInfo("Cloning registry..."))
+		fmt.Fprintln(out, tui.RenderInfo("Cloning registry..."))
 		os.MkdirAll(filepath.Dir(registryDir), 0755)
-		if err := gitMgr.CloneFullQuiet(cloneURL, registryDir); err != nil {
-			fmt.Println(tui.RenderError("Failed to clone registry: " + err.Error()))
-			return
+		cloneMgr, cloned := trackGit(gitMgr, "clone", "registry")
+		err := cloneMgr.CloneFullQuiet(cloneURL, registryDir)
+		cloned()
+		if err != nil {
+			return nil, gitError(err, "failed to clone registry")
 		}
 	} else {
 		// Already cloned — pull latest
-		fmt.Println(tui.RenderInfo("Pulling latest changes..."))
-		if err := gitMgr.PullQuiet(registryDir); err != nil {
-			fmt.Println(tui.RenderError("Failed to pull: " + err.Error()))
-			return
+		fmt.Fprintln(out, tui.RenderInfo("Pulling latest changes..."))
+		pullMgr, pulled := trackGit(gitMgr, "pull", "registry")
+		err := pullMgr.PullQuiet(registryDir)
+		pulled()
+		if err != nil {
+			return nil, gitError(err, "failed to pull")
 		}
 	}
 
@@ -94,68 +151,92 @@ func SyncSkills(interactive bool) {
 	// Scan for skills (directories containing SKILL.md)
 	foundSkills := scanForSkills(scanRoot)
 	if len(foundSkills) == 0 {
-		fmt.Println(tui.RenderWarning("No skills found in registry (no SKILL.md files)."))
-		return
+		fmt.Fprintln(out, tui.RenderWarning("No skills found in registry (no SKILL.md files)."))
+		return &SyncResult{Registry: registryURL, Skills: []SyncSkillEntry{}}, nil
 	}
 
 	// Sync each skill into the repo
 	registry := skills.NewRegistry(cm)
-	added := 0
-	updated := 0
-	unchanged := 0
-	replaced := 0
-	replacedLinks := 0
+	result := &SyncResult{Registry: registryURL, Skills: []SyncSkillEntry{}}
 	detectedProjects := project.NewDetector("").DetectAll()
+	op := beginJournal(cm, "sync")
+	defer finishJournal(op)
 
-	for _, skillDir := range foundSkills {
+	for i, skillDir := range foundSkills {
 		skillName := filepath.Base(skillDir)
 		id := "registry:" + skillName
 		destPath := cm.GetRepoPath(id)
+		reportProgress(ProgressEvent{Op: "sync", Subject: id, Phase: "Syncing skills", Percent: i * 100 / len(foundSkills), Current: i + 1, Total: len(foundSkills)})
 
-		removedSources, removedLinks := removeSkillsWithLinkName(cm, registry, skillName, id, detectedProjects)
+		removedSources, removedLinks, err := removeSkillsWithLinkName(op, cm, registry, skillName, id, detectedProjects)
 		if removedSources > 0 {
-			replaced += removedSources
-			replacedLinks += removedLinks
-			fmt.Println(tui.RenderWarning(fmt.Sprintf("  ~ %s: replaced %d existing source(s) with registry", skillName, removedSources)))
+			result.Replaced += removedSources
+			result.ReplacedLinks += removedLinks
+			fmt.Fprintln(out, tui.RenderWarning(fmt.Sprintf("  ~ %s: replaced %d existing source(s) with registry", skillName, removedSources)))
+		}
+		if err != nil {
+			fmt.Fprintln(out, tui.RenderError(err.Error()))
+			result.fail(id, err)
+			continue
 		}
 
 		existing := registry.GetSkill(id)
 
-		// Copy skill directory to repo
-		if err := os.RemoveAll(destPath); err != nil && !os.IsNotExist(err) {
-			fmt.Println(tui.RenderError("Failed to clean " + skillName + ": " + err.Error()))
+		// Get commit for this skill's path (relative to repo root, not scan root)
+		relPath, _ := filepath.Rel(registryDir, skillDir)
+		commitID, _ := gitMgr.GetLocalPathCommitID(registryDir, filepath.ToSlash(relPath))
+
+		// Copy skill directory to repo, keeping changed versions in the journal
+		if existing != nil && existing.CommitID != commitID {
+			err = op.SkillReplaced(*existing, destPath)
+		} else {
+			err = os.RemoveAll(destPath)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			fmt.Fprintln(out, tui.RenderError("Failed to clean "+skillName+": "+err.Error()))
+			result.fail(id, err)
 			continue
 		}
 
 		if err := copyDir(skillDir, destPath); err != nil {
-			fmt.Println(tui.RenderError("Failed to copy " + skillName + ": " + err.Error()))
+			fmt.Fprintln(out, tui.RenderError("Failed to copy "+skillName+": "+err.Error()))
+			result.fail(id, err)
 			continue
 		}
 
-		// Get commit for this skill's path (relative to repo root, not scan root)
-		relPath, _ := filepath.Rel(registryDir, skillDir)
-		commitID, _ := gitMgr.GetLocalPathCommitID(registryDir, filepath.ToSlash(relPath))
-
 		registry.AddSkill(id, "registry", commitID, "")
+		if existing == nil {
+			op.SkillAdded(id)
+		}
 
+		entry := SyncSkillEntry{ID: id, Commit: commitID}
 		if existing == nil {
-			fmt.Println(tui.RenderSuccess("  + " + skillName + " (new)"))
-			added++
+			fmt.Fprintln(out, tui.RenderSuccess("  + "+skillName+" (new)"))
+			entry.Action = "added"
+			result.Added++
 		} else if existing.CommitID != commitID {
-			fmt.Println(tui.RenderSuccess("  ↑ " + skillName + " (updated)"))
-			updated++
+			fmt.Fprintln(out, tui.RenderSuccess("  ↑ "+skillName+" (updated)"))
+			entry.Action = "updated"
+			result.Updated++
+			if err := refreshCopies(op, cm, gitMgr, id); err != nil {
+				fmt.Fprintln(out, tui.RenderWarning(err.Error()))
+			}
 		} else {
-			unchanged++
+			entry.Action = "unchanged"
+			result.Unchanged++
 		}
+		result.Skills = append(result.Skills, entry)
 	}
 
-	fmt.Println()
-	summary := fmt.Sprintf("Sync complete: %d new, %d updated, %d unchanged", added, updated, unchanged)
-	fmt.Println(tui.RenderSuccess(summary))
-	if replaced > 0 {
-		fmt.Println(tui.RenderInfo(fmt.Sprintf("%d duplicate source skill(s) replaced by registry", replaced)))
-		if replacedLinks > 0 {
-			fmt.Println(tui.RenderInfo(fmt.Sprintf("%d linked skill entry(s) removed for replaced sources", replacedLinks)))
+	reportProgress(ProgressEvent{Op: "sync", Subject: "registry", Percent: 100, Current: len(foundSkills), Total: len(foundSkills), Done: true})
+
+	fmt.Fprintln(out)
+	summary := fmt.Sprintf("Sync complete: %d new, %d updated, %d unchanged", result.Added, result.Updated, result.Unchanged)
+	fmt.Fprintln(out, tui.RenderSuccess(summary))
+	if result.Replaced > 0 {
+		fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("%d duplicate source skill(s) replaced by registry", result.Replaced)))
+		if result.ReplacedLinks > 0 {
+			fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("%d linked skill entry(s) removed for replaced sources", result.ReplacedLinks)))
 		}
 	}
 
@@ -166,25 +247,184 @@ func SyncSkills(interactive bool) {
 		foundSet["registry:"+filepath.Base(skillDir)] = true
 	}
 
-	removed := 0
-	linkCleanup := 0
 	for _, skill := range allSkills {
 		if skill.Type == "registry" && !foundSet[skill.ID] {
-			os.RemoveAll(cm.GetRepoPath(skill.ID))
+			if err := op.SkillRemoved(skill, cm.GetRepoPath(skill.ID)); err != nil {
+				err = fmt.Errorf("failed to remove %s: %w", skill.ID, err)
+				fmt.Fprintln(out, tui.RenderError(err.Error()))
+				result.fail(skill.ID, err)
+				continue
+			}
 			for _, p := range detectedProjects {
-				if removeSkillLinkIfPresent(cm, skill.ID, p) {
-					linkCleanup++
+				if removeSkillLinkIfPresent(op, cm, skill.ID, p) {
+					result.RemovedLinks++
 				}
 			}
 			registry.RemoveSkill(skill.ID)
-			fmt.Println(tui.RenderWarning("  - " + cm.GetLinkName(skill.ID) + " (removed from registry)"))
-			removed++
+			fmt.Fprintln(out, tui.RenderWarning("  - "+cm.GetLinkName(skill.ID)+" (removed from registry)"))
+			result.Skills = append(result.Skills, SyncSkillEntry{ID: skill.ID, Action: "removed", Commit: skill.CommitID})
+			result.Removed++
 		}
 	}
-	if removed > 0 {
-		fmt.Println(tui.RenderInfo(fmt.Sprintf("%d skill(s) removed (no longer in registry)", removed)))
-		if linkCleanup > 0 {
-			fmt.Println(tui.RenderInfo(fmt.Sprintf("%d linked skill entry(s) removed from detected project tools", linkCleanup)))
+	if result.Removed > 0 {
+		fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("%d skill(s) removed (no longer in registry)", result.Removed)))
+		if result.RemovedLinks > 0 {
+			fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("%d linked skill entry(s) removed from detected project tools", result.RemovedLinks)))
+		}
+	}
+
+	if result.Failed > 0 {
+		return result, aggregateError(result.errs, "%d skill(s) failed to sync", result.Failed)
+	}
+	return result, nil
+}
+
+// registrySkill is a skill directory found in the registry.
+type registrySkill struct {
+	name   string
+	commit string
+}
+
+// PlanSync computes what Sync would change without modifying the repo or any
+// project. An existing registry clone is fetched, which moves only its
+// remote-tracking branches, and its upstream branch inspected; the plan lists
+// the fetch. Otherwise the registry is cloned to a temporary directory.
+func PlanSync(registryURL string) (*Plan, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	gitMgr := git.NewManager()
+
+	if err := gitMgr.CheckGitVersion(); err != nil {
+		return nil, classify(KindGit, err)
+	}
+
+	plan := newPlan("sync")
+	current := cm.GetRegistry()
+	registryURL = strings.TrimSpace(registryURL)
+	if registryURL != "" && registryURL != current {
+		plan.add("set-registry", "", "", registryURL)
+	} else {
+		registryURL = current
+	}
+	if registryURL == "" {
+		return nil, newError(KindConfig, "no registry configured. Run agm sync --registry <url> or use 'Sync skills' in interactive mode")
+	}
+
+	registryDir := cm.GetRegistryDir()
+	info := gitMgr.NormalizeURL(registryURL)
+
+	var found []registrySkill
+	if _, err := os.Stat(filepath.Join(registryDir, ".git")); err == nil && registryURL == current {
+		fmt.Fprintln(out, tui.RenderInfo("Fetching registry..."))
+		if err := gitMgr.Fetch(registryDir); err != nil {
+			return nil, gitError(err, "failed to fetch registry")
+		}
+		plan.add("fetched", "", registryDir, "remote-tracking branches only")
+		found, err = listUpstreamSkills(gitMgr, registryDir, "@{upstream}", info.Path)
+		if err != nil {
+			return nil, gitError(err, "failed to read registry")
+		}
+	} else {
+		tmpDir, err := os.MkdirTemp("", "agm-plan-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		fmt.Fprintln(out, tui.RenderInfo("Cloning registry to a temporary directory..."))
+		cloneMgr, cloned := trackGit(gitMgr, "clone", "registry")
+		err = cloneMgr.CloneFullQuiet(info.URL, tmpDir)
+		cloned()
+		if err != nil {
+			return nil, gitError(err, "failed to clone registry")
+		}
+		plan.add("clone", "", registryDir, info.URL)
+
+		scanRoot := tmpDir
+		if info.Path != "" {
+			scanRoot = filepath.Join(tmpDir, info.Path)
+		}
+		for _, skillDir := range scanForSkills(scanRoot) {
+			relPath, _ := filepath.Rel(tmpDir, skillDir)
+			commitID, _ := gitMgr.GetLocalPathCommitID(tmpDir, filepath.ToSlash(relPath))
+			found = append(found, registrySkill{name: filepath.Base(skillDir), commit: commitID})
+		}
+	}
+
+	if len(found) == 0 {
+		// Sync stops without changing anything when the registry is empty.
+		fmt.Fprintln(out, tui.RenderWarning("No skills found in registry (no SKILL.md files)."))
+		return plan, nil
+	}
+	planSyncSkills(plan, cm, skills.NewRegistry(cm), found, project.NewDetector("").DetectAll())
+	return plan, nil
+}
+
+// listUpstreamSkills lists the skills under subPath at rev in a local repo,
+// mirroring scanForSkills without a checkout.
+func listUpstreamSkills(gitMgr *git.Manager, repoDir, rev, subPath string) ([]registrySkill, error) {
+	dirs, err := gitMgr.ListTreeDirs(repoDir, rev, subPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []registrySkill
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir, ".") {
+			continue
+		}
+		relPath := path.Join(subPath, dir)
+		if !gitMgr.HasPath(repoDir, rev, relPath+"/SKILL.md") {
+			continue
+		}
+		commitID, _ := gitMgr.GetRemotePathCommitID(repoDir, rev, relPath)
+		found = append(found, registrySkill{name: dir, commit: commitID})
+	}
+	return found, nil
+}
+
+// planSyncSkills adds the actions Sync would take for the found registry skills.
+func planSyncSkills(plan *Plan, cm *config.Manager, registry *skills.Registry, found []registrySkill, detectedProjects []project.Info) {
+	allSkills := registry.GetAllSkills()
+	foundSet := make(map[string]bool)
+
+	planUnlinks := func(id string) {
+		for _, p := range detectedProjects {
+			linkPath := filepath.Join(p.SkillDir, cm.GetLinkName(id))
+			if syncRemovesLink(linkPath, id) {
+				plan.add("unlink", id, linkPath, p.Type)
+			}
+		}
+	}
+
+	for _, rs := range found {
+		id := "registry:" + rs.name
+		foundSet[id] = true
+
+		for _, skill := range allSkills {
+			if skill.ID != id && cm.GetLinkName(skill.ID) == rs.name {
+				plan.add("replace", skill.ID, cm.GetRepoPath(skill.ID), "replaced by "+id)
+				planUnlinks(skill.ID)
+			}
+		}
+
+		existing := registry.GetSkill(id)
+		switch {
+		case existing == nil:
+			plan.add("add", id, "", truncate(rs.commit, 7))
+		case existing.CommitID != rs.commit:
+			plan.add("update", id, "", truncate(existing.CommitID, 7)+" → "+truncate(rs.commit, 7))
+		default:
+			plan.add("unchanged", id, "", "")
+		}
+	}
+
+	for _, skill := range allSkills {
+		if skill.Type == "registry" && !foundSet[skill.ID] {
+			plan.add("remove", skill.ID, cm.GetRepoPath(skill.ID), "no longer in registry")
+			planUnlinks(skill.ID)
 		}
 	}
 }
@@ -213,20 +453,44 @@ func scanForSkills(root string) []string {
 	return results
 }
 
-func removeSkillLinkIfPresent(cm *config.Manager, skillID string, projectInfo project.Info) bool {
+// syncRemovesLink reports whether Sync removes linkPath when skillID goes
+// away: any link, but of directories only copies made for a copy or hardlink
+// tool. Vendored copies and anything else are the user's.
+func syncRemovesLink(linkPath, skillID string) bool {
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		prov := linkedCopy(linkPath)
+		return prov != nil && prov.ID == skillID
+	}
+	return true
+}
+
+func removeSkillLinkIfPresent(op *journal.Op, cm *config.Manager, skillID string, projectInfo project.Info) bool {
 	linkPath := filepath.Join(projectInfo.SkillDir, cm.GetLinkName(skillID))
-	if _, err := os.Lstat(linkPath); err != nil {
+	if !syncRemovesLink(linkPath, skillID) {
 		return false
 	}
+	if info, _ := os.Lstat(linkPath); info.IsDir() {
+		return op.CopyRemoved(skillID, linkPath) == nil
+	}
+	target, _ := os.Readlink(linkPath)
 	if err := os.Remove(linkPath); err != nil {
 		return false
 	}
+	op.LinkRemoved(skillID, linkPath, target)
 	return true
 }
 
-func removeSkillsWithLinkName(cm *config.Manager, registry *skills.Registry, linkName, keepID string, detectedProjects []project.Info) (int, int) {
+// removeSkillsWithLinkName removes the skills other than keepID that link
+// under linkName, with their links. Skills that cannot be moved into the
+// journal are kept and reported in the error.
+func removeSkillsWithLinkName(op *journal.Op, cm *config.Manager, registry *skills.Registry, linkName, keepID string, detectedProjects []project.Info) (int, int, error) {
 	removedSources := 0
 	removedLinks := 0
+	var errs []error
 	for _, skill := range registry.GetAllSkills() {
 		if skill.ID == keepID {
 			continue
@@ -234,14 +498,17 @@ func removeSkillsWithLinkName(cm *config.Manager, registry *skills.Registry, lin
 		if cm.GetLinkName(skill.ID) != linkName {
 			continue
 		}
-		_ = os.RemoveAll(cm.GetRepoPath(skill.ID))
+		if err := op.SkillRemoved(skill, cm.GetRepoPath(skill.ID)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to replace %s: %w", skill.ID, err))
+			continue
+		}
 		for _, p := range detectedProjects {
-			if removeSkillLinkIfPresent(cm, skill.ID, p) {
+			if removeSkillLinkIfPresent(op, cm, skill.ID, p) {
 				removedLinks++
 			}
 		}
 		registry.RemoveSkill(skill.ID)
 		removedSources++
 	}
-	return removedSources, removedLinks
+	return removedSources, removedLinks, aggregateError(errs, "%d skill(s) linked as %s could not be replaced", len(errs), linkName)
 }
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// beginJournal starts recording a mutating operation so it can be undone.
+func beginJournal(cm *config.Manager, op string) *journal.Op {
+	return journal.New(cm).Begin(op)
+}
+
+// finishJournal writes a recorded operation, warning when it cannot.
+func finishJournal(op *journal.Op) {
+	if err := op.Commit(); err != nil {
+		fmt.Fprintln(out, tui.RenderWarning("Could not record the operation for undo: "+err.Error()))
+	}
+}
+
+// HistoryEntry is a journal entry and whether it has been undone.
+type HistoryEntry struct {
+	journal.Entry
+	Undone bool `json:"undone"`
+}
+
+// History returns the recorded operations, newest first.
+func History() ([]HistoryEntry, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := journal.New(cm).Entries()
+	if err != nil {
+		return nil, wrapError(KindConfig, err, "failed to read journal")
+	}
+	undone := journal.Undone(entries)
+
+	history := make([]HistoryEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		history = append(history, HistoryEntry{Entry: entries[i], Undone: undone[entries[i].ID]})
+	}
+	return history, nil
+}
+
+// Undo reverses the most recent operation that has not been undone yet and
+// returns it. Undo operations themselves cannot be undone.
+func Undo() (*journal.Entry, error) {
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	j := journal.New(cm)
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, wrapError(KindConfig, err, "failed to read journal")
+	}
+	undone := journal.Undone(entries)
+
+	var target *journal.Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Undoes == 0 && !undone[entries[i].ID] {
+			target = &entries[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, newError(KindNotFound, "nothing to undo")
+	}
+
+	fmt.Fprintln(out, tui.RenderInfo(fmt.Sprintf("Undoing #%d %s (%s)", target.ID, target.Op, target.Time.Local().Format("2006-01-02 15:04"))))
+	registry := skills.NewRegistry(cm)
+	var errs []error
+	for i := len(target.Changes) - 1; i >= 0; i-- {
+		if err := undoChange(cm, j, registry, target.Changes[i]); err != nil {
+			fmt.Fprintln(out, tui.RenderError(err.Error()))
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return target, aggregateError(errs, "%d change(s) could not be undone; #%d is left in the journal", len(errs), target.ID)
+	}
+
+	if _, err := j.Append(journal.Entry{Op: "undo", Undoes: target.ID}); err != nil {
+		return target, wrapError(KindConfig, err, "undid #%d but could not record it", target.ID)
+	}
+	return target, nil
+}
+
+func undoChange(cm *config.Manager, j *journal.Journal, registry *skills.Registry, c journal.Change) error {
+	switch c.Kind {
+	case journal.SkillAdded:
+		if err := os.RemoveAll(cm.GetRepoPath(c.SkillID)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", c.SkillID, err)
+		}
+		registry.RemoveSkill(c.SkillID)
+		fmt.Fprintln(out, tui.RenderSuccess("  - "+c.SkillID))
+
+	case journal.SkillReplaced, journal.SkillRemoved:
+		repoPath := cm.GetRepoPath(c.SkillID)
+		if c.Backup != "" {
+			backup := j.BackupPath(c)
+			_, err := os.Stat(backup)
+			switch {
+			case err != nil && pathExists(repoPath):
+				// Moved back by an earlier, partly failed undo.
+			case err != nil:
+				return newError(KindNotFound, "backup of %s is missing: %s", c.SkillID, backup)
+			default:
+				if err := restoreSkillBackup(c.SkillID, backup, repoPath); err != nil {
+					return err
+				}
+			}
+		}
+		if p := c.Previous; p != nil {
+			registry.RestoreSkill(*p)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  ↺ "+c.SkillID+" restored"))
+
+	case journal.LinkCreated:
+		if _, err := os.Lstat(c.Path); os.IsNotExist(err) {
+			return nil
+		}
+		if err := os.Remove(c.Path); err != nil {
+			return fmt.Errorf("failed to unlink %s: %w", c.Path, err)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  - "+c.Path))
+
+	case journal.LinkRemoved:
+		if _, err := os.Lstat(c.Path); err == nil {
+			if target, _ := os.Readlink(c.Path); c.Target != "" && target == c.Target {
+				return nil // restored by an earlier, partly failed undo
+			}
+			return newError(KindConflict, "cannot restore link %s: path exists", c.Path)
+		}
+		if c.Target == "" {
+			return newError(KindNotFound, "cannot restore link %s: target unknown", c.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+			return fmt.Errorf("failed to restore link %s: %w", c.Path, err)
+		}
+		if err := createLink(c.Target, c.Path); err != nil {
+			return fmt.Errorf("failed to restore link %s: %w", c.Path, err)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  + "+c.Path))
+
+	case journal.CopyCreated:
+		if err := os.RemoveAll(c.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", c.Path, err)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  - "+c.Path))
+
+	case journal.CopyRemoved:
+		backup := j.BackupPath(c)
+		if _, err := os.Lstat(c.Path); err == nil {
+			if c.Backup != "" && !pathExists(backup) {
+				// Restored by an earlier, partly failed undo.
+				if linkedCopy(c.Path) != nil {
+					registry.AddCopy(c.SkillID, c.Path)
+				}
+				return nil
+			}
+			return newError(KindConflict, "cannot restore %s: path exists", c.Path)
+		}
+		if c.Backup == "" || !pathExists(backup) {
+			return newError(KindNotFound, "backup of %s is missing: %s", c.Path, backup)
+		}
+		if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", c.Path, err)
+		}
+		if err := journal.Move(backup, c.Path); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", c.Path, err)
+		}
+		os.Remove(filepath.Dir(backup))
+		if linkedCopy(c.Path) != nil {
+			registry.AddCopy(c.SkillID, c.Path)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  + "+c.Path))
+
+	case journal.CheckedOut:
+		if err := git.NewManager().CheckoutAt(c.Path, c.Branch, c.Commit); err != nil {
+			return gitError(err, "failed to check out %s of %s again", truncate(c.Commit, 7), c.SkillID)
+		}
+		if p := c.Previous; p != nil {
+			registry.RestoreSkill(*p)
+		}
+		fmt.Fprintln(out, tui.RenderSuccess("  ↺ "+c.SkillID+" back at "+truncate(c.Commit, 7)))
+
+	default:
+		return newError(KindValidation, "unknown journal change %q", c.Kind)
+	}
+	return nil
+}
+
+// restoreSkillBackup moves a skill's repo directory back from the journal.
+func restoreSkillBackup(id, backup, repoPath string) error {
+	if err := os.RemoveAll(repoPath); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", id, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(repoPath), 0755); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", id, err)
+	}
+	if err := journal.Move(backup, repoPath); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", id, err)
+	}
+	os.Remove(filepath.Dir(backup))
+	return nil
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestUndoRestoresRemovedSkillAndLinks(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill("github:org/repo/review", "github", "abc123", "review")
+	registry.SetSource("github:org/repo/review", "vendor/review")
+	copyDir := filepath.Join(t.TempDir(), ".cursor", "skills", "review")
+	registry.AddCopy("github:org/repo/review", copyDir)
+	repoPath := cm.GetRepoPath("github:org/repo/review")
+	mustMkdirAll(t, filepath.Join(repoPath, "review"))
+	mustWriteFile(t, filepath.Join(repoPath, "review", "SKILL.md"), "review")
+
+	projectInfo := project.Info{Type: "claude", SkillDir: filepath.Join(t.TempDir(), ".claude", "skills")}
+	if _, err := Link([]string{"github:org/repo/review"}, []project.Info{projectInfo}); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	linkPath := filepath.Join(projectInfo.SkillDir, "review")
+
+	op := beginJournal(cm, "sync")
+	if _, _, err := removeSkillsWithLinkName(op, cm, registry, "review", "registry:review", []project.Info{projectInfo}); err != nil {
+		t.Fatalf("removeSkillsWithLinkName() failed: %v", err)
+	}
+	finishJournal(op)
+	if registry.GetSkill("github:org/repo/review") != nil {
+		t.Fatal("expected duplicate skill to be removed")
+	}
+
+	entry, err := Undo()
+	if err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	if entry.Op != "sync" {
+		t.Fatalf("undid %q, want sync", entry.Op)
+	}
+	if s := registry.GetSkill("github:org/repo/review"); s == nil || s.CommitID != "abc123" || s.Path != "review" {
+		t.Fatalf("registry entry not restored: %+v", s)
+	} else if s.Source != "vendor/review" || len(s.Copies) != 1 || s.Copies[0] != copyDir {
+		t.Fatalf("registry entry restored without its source and copies: %+v", s)
+	}
+	assertFileContent(t, filepath.Join(repoPath, "review", "SKILL.md"), "review")
+	if target, err := os.Readlink(linkPath); err != nil || target != filepath.Join(repoPath, "review") {
+		t.Fatalf("link not restored: target %q, err %v", target, err)
+	}
+
+	// The link operation is next, then nothing is left.
+	if entry, err := Undo(); err != nil || entry.Op != "link" {
+		t.Fatalf("second Undo() = %+v, %v; want the link", entry, err)
+	}
+	if _, err := os.Lstat(linkPath); !os.IsNotExist(err) {
+		t.Fatalf("expected link to be removed, stat err: %v", err)
+	}
+	if _, err := Undo(); KindOf(err) != KindNotFound {
+		t.Fatalf("third Undo() error kind = %v, want not-found", KindOf(err))
+	}
+}
+
+func TestReplacingKeepsSkillsThatCannotBeBackedUp(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill("local:review", "local", "", "")
+	repoPath := cm.GetRepoPath("local:review")
+	mustMkdirAll(t, repoPath)
+	mustWriteFile(t, filepath.Join(repoPath, "SKILL.md"), "review")
+	// The journal's backups cannot be created.
+	mustMkdirAll(t, filepath.Join(cm.GetHomeDir(), "journal"))
+	mustWriteFile(t, filepath.Join(cm.GetHomeDir(), "journal", "backups"), "")
+
+	op := beginJournal(cm, "sync")
+	removed, _, err := removeSkillsWithLinkName(op, cm, registry, "review", "registry:review", nil)
+	finishJournal(op)
+	if err == nil || removed != 0 {
+		t.Fatalf("removeSkillsWithLinkName() = %d, %v; want a failure", removed, err)
+	}
+	if registry.GetSkill("local:review") == nil {
+		t.Fatal("registry entry dropped although its files were not backed up")
+	}
+	assertFileContent(t, filepath.Join(repoPath, "SKILL.md"), "review")
+}
+
+func TestUndoFinishesAfterAPartialFailure(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+	registry.AddSkill("local:review", "local", "", "")
+	repoPath := cm.GetRepoPath("local:review")
+	mustMkdirAll(t, repoPath)
+	mustWriteFile(t, filepath.Join(repoPath, "SKILL.md"), "review")
+
+	skillDirs := []project.Info{
+		{Type: "claude", SkillDir: filepath.Join(t.TempDir(), ".claude", "skills")},
+		{Type: "cursor", SkillDir: filepath.Join(t.TempDir(), ".cursor", "skills")},
+	}
+	if _, err := Link([]string{"local:review"}, skillDirs); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+	op := beginJournal(cm, "sync")
+	if _, _, err := removeSkillsWithLinkName(op, cm, registry, "review", "registry:review", skillDirs); err != nil {
+		t.Fatalf("removeSkillsWithLinkName() failed: %v", err)
+	}
+	finishJournal(op)
+
+	// Something took the place of one link, so only part of the undo works.
+	blocked := filepath.Join(skillDirs[1].SkillDir, "review")
+	mustWriteFile(t, blocked, "mine")
+	if _, err := Undo(); KindOf(err) != KindConflict {
+		t.Fatalf("first Undo() error = %v, want a conflict", err)
+	}
+	if err := os.Remove(blocked); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := Undo()
+	if err != nil {
+		t.Fatalf("second Undo() failed: %v", err)
+	}
+	if entry.Op != "sync" {
+		t.Fatalf("second Undo() undid %q, want sync", entry.Op)
+	}
+	if registry.GetSkill("local:review") == nil {
+		t.Fatal("registry entry not restored")
+	}
+	assertFileContent(t, filepath.Join(repoPath, "SKILL.md"), "review")
+	for _, p := range skillDirs {
+		if target, err := os.Readlink(filepath.Join(p.SkillDir, "review")); err != nil || target != repoPath {
+			t.Fatalf("link in %s not restored: %q, %v", p.Type, target, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestUpdatePullsSkillsInParallelAndReportsFailures(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	setGitIdentity(t)
+
+	cm, err := openConfig()
+	if err != nil {
+		t.Fatalf("openConfig() failed: %v", err)
+	}
+	registry := skills.NewRegistry(cm)
+
+	// Each skill is a clone of its own upstream; a, b and c get a new commit.
+	upstreams := map[string]string{}
+	for _, name := range []string{"a", "b", "c", "d"} {
+		id := "github:org/" + name
+		work := filepath.Join(t.TempDir(), name)
+		mustMkdirAll(t, work)
+		mustRunGit(t, work, "init", "-q", "-b", "main")
+		mustWriteFile(t, filepath.Join(work, "SKILL.md"), "v1")
+		mustRunGit(t, work, "add", "-A")
+		mustRunGit(t, work, "commit", "-q", "-m", "v1")
+		mustRunGit(t, t.TempDir(), "clone", "-q", work, cm.GetRepoPath(id))
+		registry.AddSkill(id, "github", "v1", "")
+		upstreams[id] = work
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		work := upstreams["github:org/"+name]
+		mustWriteFile(t, filepath.Join(work, "SKILL.md"), "v2")
+		mustRunGit(t, work, "commit", "-q", "-am", "v2")
+	}
+	if err := os.RemoveAll(upstreams["github:org/c"]); err != nil {
+		t.Fatal(err)
+	}
+	registry.AddSkill("local:mine", "local", "", "")
+
+	ids := []string{"github:org/a", "github:org/b", "github:org/c", "github:org/d", "local:mine"}
+	result, err := Update(ids, 2)
+	if err == nil {
+		t.Fatal("expected Update() to report the unreachable skill")
+	}
+	if result.Updated != 2 || result.UpToDate != 1 || result.Failed != 1 || result.Skipped != 1 {
+		t.Fatalf("Update() = %+v, want 2 updated, 1 up to date, 1 failed, 1 skipped", result)
+	}
+	for _, id := range []string{"github:org/a", "github:org/b"} {
+		if s := registry.GetSkill(id); s == nil || s.CommitID == "v1" {
+			t.Errorf("%s version not recorded: %+v", id, s)
+		}
+		assertFileContent(t, filepath.Join(cm.GetRepoPath(id), "SKILL.md"), "v2")
+	}
+	if s := registry.GetSkill("github:org/c"); s.CommitID != "v1" {
+		t.Errorf("failed skill version changed to %s", s.CommitID)
+	}
+}
@@ -0,0 +1,359 @@
+package commands
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/git"
+	"github.com/ArdentaCorp/agent-management/internal/journal"
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/project"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// Vendor statuses of a skill in a tool's skill directory.
+const (
+	VendorCopied    = "vendored"  // a link replaced by a copy
+	VendorUpdated   = "updated"   // a copy refreshed from agm's repo
+	VendorUnchanged = "unchanged" // a copy already current
+	VendorModified  = "modified"  // a copy with local edits, left alone
+	VendorFailed    = "failed"
+)
+
+// VendorOptions controls Vendor.
+type VendorOptions struct {
+	// Dir is the project directory; empty means the current directory.
+	Dir string
+	// IDs limits Vendor to these skills; empty means every linked skill.
+	IDs []string
+	// Update refreshes existing copies instead of replacing links.
+	Update bool
+	// Force overwrites copies with local edits.
+	Force bool
+}
+
+// VendorResult summarizes agm vendor.
+type VendorResult struct {
+	Project string       `json:"project"`
+	Copies  []VendorCopy `json:"copies"`
+	Failed  int          `json:"failed"`
+}
+
+// VendorCopy is the outcome for one skill in one tool.
+type VendorCopy struct {
+	SkillID string `json:"skillId"`
+	Tool    string `json:"tool"`
+	Path    string `json:"path"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// vendorItem is a link Vendor would replace or a copy it would refresh.
+type vendorItem struct {
+	tool  string
+	path  string
+	skill *skills.Skill        // nil when the copied skill is not installed
+	prov  *manifest.Provenance // nil for links
+	// edited is set for copies that no longer match their provenance.
+	edited bool
+	// current is set for copies of the installed version of their skill.
+	current bool
+}
+
+// Vendor replaces the links to agm's skills in the detected tools of the
+// project with copies, so the skills work where agm is not installed. Each
+// copy carries a provenance marker naming its skill, source, commit and
+// content hash. With Update set it instead refreshes existing copies from
+// agm's repo, leaving copies with local edits alone unless Force is set.
+func Vendor(opts VendorOptions) (*VendorResult, error) {
+	root, err := projectRoot(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	gitMgr := git.NewManager()
+	items, err := scanVendor(cm, gitMgr, root, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	op := beginJournal(cm, "vendor")
+	defer finishJournal(op)
+	result := &VendorResult{Project: root, Copies: []VendorCopy{}}
+	var errs []error
+	vendored := false
+	for _, it := range items {
+		c := VendorCopy{Tool: it.tool, Path: it.path, Status: VendorUnchanged}
+		if it.skill != nil {
+			c.SkillID = it.skill.ID
+		} else {
+			c.SkillID = it.prov.ID
+		}
+
+		var err error
+		switch {
+		case it.prov == nil:
+			if err = vendorCopy(op, cm, gitMgr, *it.skill, root, it.path); err == nil {
+				c.Status, vendored = VendorCopied, true
+				fmt.Fprintln(out, tui.RenderSuccess("Vendored "+c.SkillID+" into "+it.tool))
+			}
+		case !opts.Update:
+			fmt.Fprintln(out, tui.MutedText.Render("  "+c.SkillID+" is already vendored in "+it.tool))
+		case it.edited && !opts.Force:
+			c.Status = VendorModified
+			err = newError(KindConflict, "%s in %s was edited since it was vendored; use --force to overwrite it", c.SkillID, it.tool)
+		case it.skill == nil:
+			err = newError(KindNotFound, "%s is not installed; run agm install to refresh its copy", c.SkillID)
+		case it.current && !it.edited:
+			fmt.Fprintln(out, tui.MutedText.Render("  "+c.SkillID+" unchanged in "+it.tool))
+		default:
+			if err = vendorCopy(op, cm, gitMgr, *it.skill, root, it.path); err == nil {
+				c.Status = VendorUpdated
+				fmt.Fprintln(out, tui.RenderSuccess("Updated "+c.SkillID+" in "+it.tool))
+			}
+		}
+		if err != nil {
+			if c.Status != VendorModified {
+				c.Status = VendorFailed
+			}
+			c.Error = err.Error()
+			fmt.Fprintln(out, tui.RenderError(err.Error()))
+			errs = append(errs, err)
+			result.Failed++
+		}
+		result.Copies = append(result.Copies, c)
+	}
+	if vendored {
+		refreshIgnoreBlock(cm, root)
+	}
+
+	if len(result.Copies) == 0 {
+		if opts.Update {
+			fmt.Fprintln(out, tui.RenderInfo("No vendored skills in "+root))
+		} else {
+			fmt.Fprintln(out, tui.RenderInfo("No linked skills to vendor in "+root))
+		}
+	}
+	if len(errs) > 0 {
+		return result, aggregateError(errs, "%d of %d skill(s) could not be vendored", len(errs), len(result.Copies))
+	}
+	return result, nil
+}
+
+// PlanVendor returns the links Vendor would replace and the copies it would
+// refresh.
+func PlanVendor(opts VendorOptions) (*Plan, error) {
+	root, err := projectRoot(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	items, err := scanVendor(cm, git.NewManager(), root, opts)
+	if err != nil {
+		return nil, err
+	}
+	plan := newPlan("vendor")
+	for _, it := range items {
+		switch {
+		case it.prov == nil:
+			plan.add("replace", it.skill.ID, it.path, it.tool+", link becomes a copy")
+		case !opts.Update:
+			plan.add("unchanged", it.prov.ID, it.path, it.tool)
+		case it.edited && !opts.Force:
+			plan.add("unchanged", it.prov.ID, it.path, it.tool+", edited since it was vendored")
+		case it.skill == nil:
+			plan.add("unchanged", it.prov.ID, it.path, it.tool+", not installed")
+		case it.current && !it.edited:
+			plan.add("unchanged", it.prov.ID, it.path, it.tool)
+		default:
+			detail := it.tool
+			if it.edited {
+				detail += ", overwrites local edits"
+			}
+			plan.add("update", it.prov.ID, it.path, detail)
+		}
+	}
+	return plan, nil
+}
+
+// scanVendor finds the links, or with opts.Update the copies, that Vendor
+// acts on in root's detected tools.
+func scanVendor(cm *config.Manager, gitMgr *git.Manager, root string, opts VendorOptions) ([]vendorItem, error) {
+	registry := skills.NewRegistry(cm)
+	var items []vendorItem
+	found := make(map[string]bool)
+	for _, p := range project.NewDetector(root).DetectAll() {
+		entries, _ := os.ReadDir(p.SkillDir)
+		for _, entry := range entries {
+			path := filepath.Join(p.SkillDir, entry.Name())
+			info, err := os.Lstat(path)
+			if _, staging := git.StagingPID(entry.Name()); err != nil || staging {
+				continue
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				target, err := readLinkTarget(path)
+				if err != nil || opts.Update {
+					continue
+				}
+				skill := registry.GetSkill(linkSkillID(cm, target))
+				if skill == nil || !pathExists(path) || (len(opts.IDs) > 0 && !slices.Contains(opts.IDs, skill.ID)) {
+					continue
+				}
+				found[skill.ID] = true
+				items = append(items, vendorItem{tool: p.Type, path: path, skill: skill})
+				continue
+			}
+
+			if !info.IsDir() {
+				continue
+			}
+			prov, err := manifest.ReadProvenance(path)
+			if err != nil {
+				return nil, wrapError(KindValidation, err, "failed to read the provenance of %s", path)
+			}
+			if prov == nil || prov.LinkMode != "" || (len(opts.IDs) > 0 && !slices.Contains(opts.IDs, prov.ID)) {
+				continue // not vendored, or installed by the tool's link mode
+			}
+			found[prov.ID] = true
+			it := vendorItem{tool: p.Type, path: path, skill: registry.GetSkill(prov.ID), prov: prov}
+			if opts.Update {
+				hash, err := manifest.HashDir(path)
+				it.edited = err != nil || hash != prov.Hash
+				if it.skill != nil {
+					entry, err := lockEntry(cm, gitMgr, *it.skill, root)
+					it.current = err == nil && entry.Hash == prov.Hash
+				}
+			}
+			items = append(items, it)
+		}
+	}
+	for _, id := range opts.IDs {
+		if !found[id] {
+			if opts.Update {
+				return nil, newError(KindNotFound, "%s is not vendored in %s", id, root)
+			}
+			return nil, newError(KindNotFound, "%s is not linked in %s", id, root)
+		}
+	}
+	return items, nil
+}
+
+// vendorCopy puts a vendored copy of skill where the link or previous copy at
+// path is.
+func vendorCopy(op *journal.Op, cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, root, path string) error {
+	prov, err := copyProvenance(cm, gitMgr, skill, root, "")
+	if err != nil {
+		return err
+	}
+	return replaceWithCopy(op, cm, skill, prov, path)
+}
+
+// copyProvenance returns the provenance of a copy of the installed skill,
+// made for the project at root with linkMode.
+func copyProvenance(cm *config.Manager, gitMgr *git.Manager, skill skills.Skill, root, linkMode string) (manifest.Provenance, error) {
+	entry, err := lockEntry(cm, gitMgr, skill, root)
+	if err != nil {
+		return manifest.Provenance{}, err
+	}
+	return manifest.Provenance{ID: skill.ID, Source: entry.Source, Branch: entry.Branch, Commit: entry.Commit, Hash: entry.Hash, LinkMode: linkMode}, nil
+}
+
+// replaceWithCopy puts a copy of skill, with prov as its provenance marker,
+// where the link or previous copy at path is. The files of a copy made for
+// the hardlink mode are hard links into agm's repo. The copy is staged next to
+// path first, so a failure leaves path as it was.
+func replaceWithCopy(op *journal.Op, cm *config.Manager, skill skills.Skill, prov manifest.Provenance, path string) error {
+	staging := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+git.CopyStagingInfix+strconv.Itoa(os.Getpid()))
+	os.RemoveAll(staging)
+	if err := copySkillDir(skillTargetPath(cm, skill), staging, prov.LinkMode == config.LinkModeHardlink); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to copy %s: %w", skill.ID, err)
+	}
+	if err := manifest.WriteProvenance(staging, prov); err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("failed to mark the copy of %s: %w", skill.ID, err)
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case err != nil:
+	case info.Mode()&os.ModeSymlink != 0:
+		target, _ := os.Readlink(path)
+		if err := os.Remove(path); err != nil {
+			os.RemoveAll(staging)
+			return fmt.Errorf("failed to remove the link to %s: %w", skill.ID, err)
+		}
+		op.LinkRemoved(skill.ID, path, target)
+	default:
+		if err := op.CopyRemoved(skill.ID, path); err != nil {
+			os.RemoveAll(staging)
+			return err
+		}
+	}
+	if err := os.Rename(staging, path); err != nil {
+		return fmt.Errorf("failed to move the copy of %s into place: %w", skill.ID, err)
+	}
+	op.CopyCreated(skill.ID, path)
+	return nil
+}
+
+// isCopyOf reports whether dir is a copy of skill id made by agm. Copies
+// still being staged are not.
+func isCopyOf(dir, id string) bool {
+	if _, staging := git.StagingPID(filepath.Base(dir)); staging {
+		return false
+	}
+	prov, err := manifest.ReadProvenance(dir)
+	return err == nil && prov != nil && prov.ID == id
+}
+
+// copySkillDir copies the files of a skill, keeping symlinks and file modes
+// and leaving out .git. With hardlink set, files are hard linked instead,
+// falling back to copies across file systems.
+func copySkillDir(src, dst string, hardlink bool) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			if d.Name() == ".git" && path != src {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(dest, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dest)
+		case rel == manifest.ProvenanceFileName:
+			return nil
+		case hardlink && os.Link(path, dest) == nil:
+			return nil
+		default:
+			return copyFile(path, dest, info.Mode().Perm())
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+)
+
+func TestVendorCopiesAndRefreshesSkills(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	mustMkdirAll(t, filepath.Join(src, "notes"))
+	mustWriteFile(t, filepath.Join(src, "notes", "SKILL.md"), "v1")
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:notes"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	copyDir := filepath.Join(projects[0].SkillDir, "notes")
+	result, err := Vendor(VendorOptions{})
+	if err != nil || len(result.Copies) != 1 || result.Copies[0].Status != VendorCopied {
+		t.Fatalf("Vendor() = %+v, %v", result, err)
+	}
+	if info, err := os.Lstat(copyDir); err != nil || !info.IsDir() {
+		t.Fatalf("%s is not a directory after vendoring", copyDir)
+	}
+	prov, err := manifest.ReadProvenance(copyDir)
+	if err != nil || prov == nil || prov.ID != "local:notes" || prov.Hash == "" {
+		t.Fatalf("provenance = %+v, %v", prov, err)
+	}
+	if report, _ := Status(""); !report.Clean {
+		t.Fatalf("status of a fresh copy is not clean: %+v", report.Tools)
+	}
+	// Linking again keeps the copy.
+	if _, err := Link([]string{"local:notes"}, projects); err != nil {
+		t.Fatalf("Link() over a copy failed: %v", err)
+	}
+
+	cm, _ := openConfig()
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:notes"), "SKILL.md"), "v2")
+	if result, err = Vendor(VendorOptions{Update: true}); err != nil || result.Copies[0].Status != VendorUpdated {
+		t.Fatalf("Vendor(Update) = %+v, %v", result, err)
+	}
+	assertFileContent(t, filepath.Join(copyDir, "SKILL.md"), "v2")
+
+	mustWriteFile(t, filepath.Join(copyDir, "SKILL.md"), "local edit")
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:notes"), "SKILL.md"), "v3")
+	if _, err = Vendor(VendorOptions{Update: true}); KindOf(err) != KindConflict {
+		t.Fatalf("updating an edited copy = %v, want a conflict", err)
+	}
+	assertFileContent(t, filepath.Join(copyDir, "SKILL.md"), "local edit")
+	if _, err = Vendor(VendorOptions{Update: true, Force: true}); err != nil {
+		t.Fatalf("Vendor(Force) failed: %v", err)
+	}
+	assertFileContent(t, filepath.Join(copyDir, "SKILL.md"), "v3")
+
+	// Undo puts the edited copy back.
+	if _, err := Undo(); err != nil {
+		t.Fatalf("Undo() failed: %v", err)
+	}
+	assertFileContent(t, filepath.Join(copyDir, "SKILL.md"), "local edit")
+}
+
+func TestLeakedVendorStagingIsIgnoredAndCollected(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	// A copy staged by a process that was killed before moving it into place.
+	skillDir := filepath.Join(proj, ".claude", "skills")
+	leaked := filepath.Join(skillDir, ".notes.vendor-999999999")
+	mustMkdirAll(t, leaked)
+	mustWriteFile(t, filepath.Join(leaked, "SKILL.md"), "notes")
+	if err := manifest.WriteProvenance(leaked, manifest.Provenance{ID: "local:notes", Hash: "x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if report, err := Status(""); err != nil || !report.Clean {
+		t.Fatalf("Status() with a leaked staging dir = %+v, %v", report, err)
+	}
+	if result, err := Vendor(VendorOptions{Update: true}); err != nil || len(result.Copies) != 0 {
+		t.Fatalf("Vendor(Update) = %+v, %v; want the staging dir skipped", result, err)
+	}
+	if _, err := GC(GCOptions{}); err != nil {
+		t.Fatalf("GC() failed: %v", err)
+	}
+	if _, err := os.Stat(leaked); !os.IsNotExist(err) {
+		t.Fatalf("GC() kept %s", leaked)
+	}
+}
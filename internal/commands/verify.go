@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ArdentaCorp/agent-management/internal/manifest"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+	"github.com/ArdentaCorp/agent-management/internal/tui"
+)
+
+// Verify checks.
+const (
+	VerifyLink     = "link"     // a symlink that does not resolve to a registered skill
+	VerifySkill    = "skill"    // a linked skill without a valid SKILL.md
+	VerifyManifest = "manifest" // links that differ from .agm.json
+	VerifyLock     = "lock"     // a linked skill that differs from agm.lock
+)
+
+// VerifyReport lists every inconsistency in a project's skill setup.
+type VerifyReport struct {
+	Project  string          `json:"project"`
+	Manifest bool            `json:"manifest"`
+	Lock     bool            `json:"lock"`
+	Checked  int             `json:"checked"` // entries inspected across all tools
+	Problems []VerifyProblem `json:"problems"`
+}
+
+// VerifyProblem is one inconsistency found by Verify.
+type VerifyProblem struct {
+	Check   string `json:"check"`
+	Tool    string `json:"tool,omitempty"`
+	Name    string `json:"name,omitempty"`
+	SkillID string `json:"skillId,omitempty"`
+	Message string `json:"message"`
+}
+
+// Verify checks, without prompting or touching the network, that the project
+// in dir (the current directory if empty) is consistent: every symlink in the
+// detected tools' skill directories resolves to a registered skill, every
+// linked or vendored skill has a SKILL.md with a name and description, links match
+// .agm.json when the project has one, and linked skills match agm.lock when
+// the project has one. It returns a KindDrift error when problems are found.
+func Verify(dir string) (*VerifyReport, error) {
+	status, err := Status(dir)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := openConfig()
+	if err != nil {
+		return nil, err
+	}
+	registry := skills.NewRegistry(cm)
+	m, err := manifest.Load(status.Project)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read manifest")
+	}
+	lock, err := manifest.LoadLock(status.Project)
+	if err != nil {
+		return nil, wrapError(KindValidation, err, "failed to read lockfile")
+	}
+
+	report := &VerifyReport{Project: status.Project, Manifest: status.Manifest, Lock: status.Lock, Problems: []VerifyProblem{}}
+	problem := func(check string, t ToolStatus, e StatusEntry, format string, args ...any) {
+		report.Problems = append(report.Problems, VerifyProblem{
+			Check: check, Tool: t.Tool, Name: e.Name, SkillID: e.SkillID, Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, t := range status.Tools {
+		for _, e := range t.Entries {
+			report.Checked++
+			switch e.State {
+			case StateBroken:
+				problem(VerifyLink, t, e, "%s: %s", e.Name, e.Detail)
+			case StateUnmanaged:
+				// Directories are the user's own; links must point into agm's repo.
+				if isUnmanagedLink(t, e) {
+					problem(VerifyLink, t, e, "%s is a %s, not a registered skill", e.Name, e.Detail)
+				}
+			case StateCollision, StateMissing, StateUndeclared:
+				problem(VerifyManifest, t, e, "%s is %s: %s", e.Name, e.State, e.Detail)
+			case StateModified:
+				problem(VerifyLock, t, e, "%s: %s", e.SkillID, e.Detail)
+			case StateOutdated:
+				// Without a lock entry this only means the remote moved on.
+				if lock.Get(e.SkillID) != nil {
+					problem(VerifyLock, t, e, "%s: %s", e.SkillID, e.Detail)
+				}
+			}
+			if e.SkillID == "" || e.State == StateBroken || e.State == StateMissing {
+				continue
+			}
+			skillDir := filepath.Join(t.SkillDir, e.Name)
+			if info, err := os.Lstat(skillDir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+				// A link: check the installed skill it points to.
+				skillDir = ""
+				if skill := registry.GetSkill(e.SkillID); skill != nil {
+					skillDir = skillTargetPath(cm, *skill)
+				}
+			}
+			if skillDir != "" {
+				if msg := checkSkillFile(skillDir); msg != "" {
+					problem(VerifySkill, t, e, "%s %s", e.SkillID, msg)
+				}
+			}
+		}
+	}
+
+	detected := make(map[string]bool)
+	for _, t := range status.Tools {
+		detected[t.Tool] = true
+	}
+	for _, s := range m.Skills {
+		for _, tool := range s.Tools {
+			if !detected[tool] {
+				report.Problems = append(report.Problems, VerifyProblem{
+					Check: VerifyManifest, Tool: tool, SkillID: s.ID,
+					Message: fmt.Sprintf("%s is declared for %s, which is not set up in this project", s.ID, tool),
+				})
+			}
+		}
+		if lock.Exists() && lock.Get(s.ID) == nil {
+			report.Problems = append(report.Problems, VerifyProblem{
+				Check: VerifyLock, SkillID: s.ID,
+				Message: fmt.Sprintf("%s is declared in %s but not locked in %s", s.ID, manifest.FileName, manifest.LockFileName),
+			})
+		}
+	}
+
+	if n := len(report.Problems); n > 0 {
+		return report, newError(KindDrift, "verify found %d problem(s)", n)
+	}
+	return report, nil
+}
+
+// checkSkillFile returns what is wrong with the SKILL.md in skillDir, or "".
+func checkSkillFile(skillDir string) string {
+	meta, err := skills.ReadMetadata(skillDir)
+	switch {
+	case err != nil:
+		return "has no readable SKILL.md"
+	case meta.Name == "":
+		return "has no name in its SKILL.md frontmatter"
+	case meta.Description == "":
+		return "has no description in its SKILL.md frontmatter"
+	}
+	return ""
+}
+
+// Print writes the report in human-readable form.
+func (r *VerifyReport) Print() {
+	if len(r.Problems) == 0 {
+		fmt.Fprintln(out, tui.RenderSuccess(fmt.Sprintf("Verified %d skill(s) in %s", r.Checked, r.Project)))
+		return
+	}
+	for _, p := range r.Problems {
+		line := p.Message
+		if p.Tool != "" {
+			line = tui.MutedText.Render(p.Tool+": ") + line
+		}
+		fmt.Fprintln(out, tui.ErrorText.Render("  ✗ ")+tui.MutedText.Render("["+p.Check+"] ")+line)
+	}
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyFailsOnDrift(t *testing.T) {
+	tempHome := t.TempDir()
+	t.Setenv("HOME", tempHome)
+	t.Setenv("USERPROFILE", tempHome)
+	proj := t.TempDir()
+	t.Chdir(proj)
+
+	src := t.TempDir()
+	for _, name := range []string{"good", "bare", "dropped"} {
+		mustMkdirAll(t, filepath.Join(src, name))
+		mustWriteFile(t, filepath.Join(src, name, "SKILL.md"), "---\nname: "+name+"\ndescription: A skill.\n---\n")
+	}
+	if _, err := AddFolder(src, AddOptions{}); err != nil {
+		t.Fatalf("AddFolder() failed: %v", err)
+	}
+	mustMkdirAll(t, filepath.Join(proj, ".claude"))
+	projects, err := SelectProjects("", nil, false)
+	if err != nil {
+		t.Fatalf("SelectProjects() failed: %v", err)
+	}
+	if _, err := Link([]string{"local:good", "local:bare", "local:dropped"}, projects); err != nil {
+		t.Fatalf("Link() failed: %v", err)
+	}
+
+	report, err := Verify("")
+	if err != nil {
+		t.Fatalf("Verify() of a consistent project failed: %v (%+v)", err, report.Problems)
+	}
+	if report.Checked != 3 {
+		t.Fatalf("checked %d entries, want 3", report.Checked)
+	}
+
+	skillDir := projects[0].SkillDir
+	cm, _ := openConfig()
+	mustWriteFile(t, filepath.Join(cm.GetRepoPath("local:bare"), "SKILL.md"), "---\nname: bare\n---\n")
+	os.Remove(filepath.Join(skillDir, "dropped"))
+	if err := os.Symlink(t.TempDir(), filepath.Join(skillDir, "elsewhere")); err != nil {
+		t.Fatal(err)
+	}
+	mustMkdirAll(t, filepath.Join(skillDir, "handmade"))
+
+	report, err = Verify("")
+	if KindOf(err) != KindDrift {
+		t.Fatalf("Verify() error = %v, want a drift error", err)
+	}
+	got := make(map[string]string)
+	for _, p := range report.Problems {
+		got[p.Name] = p.Check
+	}
+	want := map[string]string{
+		"bare":      VerifySkill,
+		"dropped":   VerifyManifest,
+		"elsewhere": VerifyLink,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("problems = %+v, want %v", report.Problems, want)
+	}
+	for name, check := range want {
+		if got[name] != check {
+			t.Errorf("%s failed check %q, want %q", name, got[name], check)
+		}
+	}
+}
@@ -13,13 +13,34 @@ import (
 type AIToolConfig struct {
 	Type      string   `json:"type"`
 	SkillDirs []string `json:"skillDirs"`
+	// LinkMode is how skills are installed into the tool's skill directory:
+	// LinkModeSymlink (the default), LinkModeCopy or LinkModeHardlink.
+	LinkMode string `json:"linkMode,omitempty"`
 }
 
+// Link modes of an AI tool. Tools that do not follow symlinked skill
+// directories get copies, or copies whose files are hard links into agm's repo.
+const (
+	LinkModeSymlink  = "symlink"
+	LinkModeCopy     = "copy"
+	LinkModeHardlink = "hardlink"
+)
+
 // Config represents the global skm configuration file.
 type Config struct {
 	System   string         `json:"system"`
 	Registry string         `json:"registry,omitempty"`
 	AITools  []AIToolConfig `json:"aiTools,omitempty"`
+	// GitTimeout limits each git command, as a Go duration such as "90s".
+	// "0" disables the limit; empty uses the default.
+	GitTimeout string `json:"gitTimeout,omitempty"`
+	// IgnoreLinks keeps the links agm creates out of git in every project:
+	// "gitignore" lists them in the project's .gitignore, "exclude" in
+	// .git/info/exclude. A project's .agm.json can override it.
+	IgnoreLinks string `json:"ignoreLinks,omitempty"`
+	// LinkStrategy is how links point at skills in every project: "absolute",
+	// "relative" or "home". A project's .agm.json can override it.
+	LinkStrategy string `json:"linkStrategy,omitempty"`
 }
 
 // Manager handles configuration paths and operations.
@@ -29,14 +50,49 @@ type Manager struct {
 	configFile string
 }
 
-// NewManager creates a new config manager and ensures directories exist.
-func NewManager() (*Manager, error) {
+// DirName is the agm home directory's name inside the user's home directory.
+const DirName = ".agent-management"
+
+// homeDirPath returns the agm home directory without creating it: $AGM_HOME
+// if set, otherwise DirName in the user's home directory.
+func homeDirPath() (string, error) {
+	if dir := os.Getenv("AGM_HOME"); dir != "" {
+		return filepath.Abs(dir)
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("cannot determine home directory: %w", err)
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, DirName), nil
+}
+
+// Load reads the configuration without creating the agm home directory.
+// A missing config file yields an empty configuration.
+func Load() (*Config, error) {
+	homeDir, err := homeDirPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(homeDir, "config.json"))
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
 	}
+	return &cfg, nil
+}
 
-	homeDir := filepath.Join(home, ".agent-management")
+// NewManager creates a new config manager and ensures directories exist.
+func NewManager() (*Manager, error) {
+	homeDir, err := homeDirPath()
+	if err != nil {
+		return nil, err
+	}
 	repoDir := filepath.Join(homeDir, "repo")
 	configFile := filepath.Join(homeDir, "config.json")
 
@@ -159,6 +215,26 @@ func (m *Manager) GetRegistry() string {
 	return cfg.Registry
 }
 
+// GetIgnoreLinks returns the configured ignoreLinks mode, or empty string if
+// not set.
+func (m *Manager) GetIgnoreLinks() string {
+	cfg, err := m.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.IgnoreLinks
+}
+
+// GetLinkStrategy returns the configured linkStrategy, or empty string if not
+// set.
+func (m *Manager) GetLinkStrategy() string {
+	cfg, err := m.LoadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.LinkStrategy
+}
+
 // SetRegistry saves the registry URL to config.
 func (m *Manager) SetRegistry(url string) error {
 	cfg, err := m.LoadConfig()
@@ -0,0 +1,140 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// trace receives a log line for every git invocation when non-nil.
+var (
+	trace   io.Writer
+	traceMu sync.Mutex // keeps the lines of concurrent commands together
+)
+
+// DefaultTimeout is how long a single git command may run unless SetTimeout
+// changes it.
+const DefaultTimeout = 10 * time.Minute
+
+var (
+	defaultCtx = context.Background()
+	timeout    = DefaultTimeout
+)
+
+// SetContext sets the context that managers created afterwards run git
+// under. Cancelling it kills their running git commands.
+func SetContext(ctx context.Context) {
+	defaultCtx = ctx
+}
+
+// SetTimeout limits how long a single git command may run. Zero removes the limit.
+func SetTimeout(d time.Duration) {
+	timeout = d
+}
+
+// SetTrace logs each git command line, working directory, duration and
+// captured stderr to w. A nil w turns tracing off.
+func SetTrace(w io.Writer) {
+	trace = w
+}
+
+// CommandError is a failed git invocation. Its message includes git's stderr.
+type CommandError struct {
+	Args   []string
+	Dir    string
+	Stderr string
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	msg := "git " + strings.Join(e.Args, " ") + ": " + e.Err.Error()
+	if e.Stderr != "" {
+		var lines []string
+		for _, l := range strings.Split(e.Stderr, "\n") {
+			if l = strings.TrimSpace(l); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		msg += ": " + strings.Join(lines, " ")
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// run executes git in dir (the current directory if empty). Stdout goes to
+// stdout and stderr is copied to stderr; either may be nil to discard it.
+// Stderr is always captured for the returned error and the trace.
+func (m *Manager) run(dir string, stdout, stderr io.Writer, args ...string) error {
+	ctx := m.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var captured bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// Helpers such as git-remote-https can outlive a killed git and hold its
+	// pipes open; stop waiting for them.
+	cmd.WaitDelay = 5 * time.Second
+	cmd.Dir = dir
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.Stderr = &captured
+	if stderr != nil {
+		cmd.Stderr = io.MultiWriter(stderr, &captured)
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		err = ctxErr
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s: %w", timeout, ctxErr)
+		}
+	}
+	errText := stripProgress(captured.String())
+	logCommand(dir, args, time.Since(start), errText, err)
+	if err != nil {
+		return &CommandError{Args: args, Dir: dir, Stderr: strings.TrimSpace(errText), Err: err}
+	}
+	return nil
+}
+
+// output executes git in dir and returns its stdout.
+func (m *Manager) output(dir string, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := m.run(dir, &stdout, nil, args...)
+	return stdout.Bytes(), err
+}
+
+func logCommand(dir string, args []string, elapsed time.Duration, stderr string, err error) {
+	if trace == nil {
+		return
+	}
+	traceMu.Lock()
+	defer traceMu.Unlock()
+	line := "[git] git " + strings.Join(args, " ")
+	if dir != "" {
+		line += " (in " + dir + ")"
+	}
+	fmt.Fprintf(trace, "%s %s\n", line, elapsed.Round(time.Millisecond))
+	for _, l := range strings.Split(strings.TrimSpace(stderr), "\n") {
+		if l != "" {
+			fmt.Fprintln(trace, "[git]   "+l)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(trace, "[git]   "+err.Error())
+	}
+}
@@ -1,11 +1,12 @@
 package git
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -20,16 +21,25 @@ type URLInfo struct {
 }
 
 // Manager handles all git operations.
-type Manager struct{}
+type Manager struct {
+	ctx      context.Context
+	progress func(Progress)
+}
 
-// NewManager creates a new git manager.
+// NewManager creates a new git manager that runs git under the context set
+// with SetContext.
 func NewManager() *Manager {
-	return &Manager{}
+	return &Manager{ctx: defaultCtx}
+}
+
+// WithContext returns a copy of m that runs git under ctx.
+func (m *Manager) WithContext(ctx context.Context) *Manager {
+	return &Manager{ctx: ctx, progress: m.progress}
 }
 
 // CheckGitVersion ensures git >= 2.25 is installed (required for sparse-checkout).
 func (m *Manager) CheckGitVersion() error {
-	out, err := exec.Command("git", "--version").Output()
+	out, err := m.output("", "--version")
 	if err != nil {
 		return fmt.Errorf("git is not installed or not in PATH: %w", err)
 	}
@@ -108,7 +118,7 @@ func (m *Manager) GetRemoteHead(url string, branch string) (string, error) {
 	if branch == "" {
 		branch = "HEAD"
 	}
-	out, err := exec.Command("git", "ls-remote", url, branch).Output()
+	out, err := m.output("", "ls-remote", url, branch)
 	if err != nil {
 		return "", fmt.Errorf("failed to get remote HEAD for %s %s: %w", url, branch, err)
 	}
@@ -120,20 +130,69 @@ func (m *Manager) GetRemoteHead(url string, branch string) (string, error) {
 	return matches[1], nil
 }
 
+// stagingInfix separates a destination's name from the process ID in the
+// name of its staging directory.
+const stagingInfix = ".clone-"
+
+// CopyStagingInfix does the same for the skill copies agm stages in a
+// project's tool skill directories.
+const CopyStagingInfix = ".vendor-"
+
+// StagingPID reports whether name is a clone or skill copy staging directory
+// and returns the ID of the process that created it.
+func StagingPID(name string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, ".")
+	if !ok {
+		return 0, false
+	}
+	for _, infix := range []string{stagingInfix, CopyStagingInfix} {
+		if i := strings.LastIndex(rest, infix); i >= 0 {
+			pid, err := strconv.Atoi(rest[i+len(infix):])
+			return pid, err == nil
+		}
+	}
+	return 0, false
+}
+
+// StagingDir returns the hidden sibling of dest that agm fills before
+// renaming it to dest, so that StagingPID recognizes it.
+func StagingDir(dest string) string {
+	return filepath.Join(filepath.Dir(dest), "."+filepath.Base(dest)+stagingInfix+strconv.Itoa(os.Getpid()))
+}
+
+// staged runs clone into a hidden sibling of dest and renames the result to
+// dest only when it succeeds, so an interrupted clone never looks installed.
+func staged(dest string, clone func(dir string) error) error {
+	dir := StagingDir(dest)
+	os.RemoveAll(dir)
+	if err := clone(dir); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	// git clone accepts an existing empty directory; so must we.
+	os.Remove(dest)
+	if err := os.Rename(dir, dest); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("failed to move clone into place: %w", err)
+	}
+	return nil
+}
+
 // CloneFull performs a full git clone.
 func (m *Manager) CloneFull(url, dest string) error {
-	cmd := exec.Command("git", "clone", url, dest)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return staged(dest, func(dir string) error {
+		return m.run("", os.Stdout, os.Stderr, "clone", url, dir)
+	})
 }
 
-// CloneFullQuiet performs a full git clone with no output.
+// CloneFullQuiet performs a full git clone with no output. Progress is
+// reported to the callback set with WithProgress, if any.
 func (m *Manager) CloneFullQuiet(url, dest string) error {
-	cmd := exec.Command("git", "clone", "--quiet", url, dest)
-	cmd.Stdout = io.Discard
-	cmd.Stderr = io.Discard
-	return cmd.Run()
+	flags, progress := m.progressArgs()
+	return staged(dest, func(dir string) error {
+		args := append([]string{"clone"}, flags...)
+		return m.run("", nil, stderrOf(progress), append(args, url, dir)...)
+	})
 }
 
 // CloneSparse performs a sparse checkout of a specific subdirectory.
@@ -141,7 +200,8 @@ func (m *Manager) CloneSparse(url, dest, subPath, branch string) error {
 	return m.cloneSparse(url, dest, subPath, branch, false)
 }
 
-// CloneSparseQuiet performs a sparse checkout with no output.
+// CloneSparseQuiet performs a sparse checkout with no output. Progress is
+// reported to the callback set with WithProgress, if any.
 func (m *Manager) CloneSparseQuiet(url, dest, subPath, branch string) error {
 	return m.cloneSparse(url, dest, subPath, branch, true)
 }
@@ -150,44 +210,38 @@ func (m *Manager) cloneSparse(url, dest, subPath, branch string, quiet bool) err
 	if branch == "" {
 		branch = "main"
 	}
+	return staged(dest, func(dir string) error {
+		return m.sparseCheckout(url, dir, subPath, branch, quiet)
+	})
+}
+
+func (m *Manager) sparseCheckout(url, dest, subPath, branch string, quiet bool) error {
+
+	var stdout, stderr io.Writer
+	if !quiet {
+		stdout, stderr = os.Stdout, os.Stderr
+	}
 
 	// Clone with blob filter and no checkout
 	args := []string{"clone", "--filter=blob:none", "--no-checkout"}
+	cloneStderr := stderr
 	if quiet {
-		args = append(args, "--quiet")
+		flags, progress := m.progressArgs()
+		args = append(args, flags...)
+		cloneStderr = stderrOf(progress)
 	}
 	args = append(args, url, dest)
-	cmd := exec.Command("git", args...)
-	if quiet {
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	} else {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-	if err := cmd.Run(); err != nil {
+	if err := m.run("", stdout, cloneStderr, args...); err != nil {
 		return fmt.Errorf("sparse clone failed: %w", err)
 	}
 
 	// Init sparse checkout
-	cmd = exec.Command("git", "sparse-checkout", "init", "--cone")
-	cmd.Dir = dest
-	if quiet {
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	}
-	if err := cmd.Run(); err != nil {
+	if err := m.run(dest, stdout, stderr, "sparse-checkout", "init", "--cone"); err != nil {
 		return fmt.Errorf("sparse-checkout init failed: %w", err)
 	}
 
 	// Set sparse checkout path
-	cmd = exec.Command("git", "sparse-checkout", "set", subPath)
-	cmd.Dir = dest
-	if quiet {
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	}
-	if err := cmd.Run(); err != nil {
+	if err := m.run(dest, stdout, stderr, "sparse-checkout", "set", subPath); err != nil {
 		return fmt.Errorf("sparse-checkout set failed: %w", err)
 	}
 
@@ -196,16 +250,7 @@ func (m *Manager) cloneSparse(url, dest, subPath, branch string, quiet bool) err
 	if quiet {
 		args = append(args, "--quiet")
 	}
-	cmd = exec.Command("git", args...)
-	cmd.Dir = dest
-	if quiet {
-		cmd.Stdout = io.Discard
-		cmd.Stderr = io.Discard
-	} else {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-	if err := cmd.Run(); err != nil {
+	if err := m.run(dest, stdout, stderr, args...); err != nil {
 		return fmt.Errorf("checkout %s failed: %w", branch, err)
 	}
 
@@ -214,27 +259,45 @@ func (m *Manager) cloneSparse(url, dest, subPath, branch string, quiet bool) err
 
 // Pull runs git pull in the given directory.
 func (m *Manager) Pull(cwd string) error {
-	cmd := exec.Command("git", "pull")
-	cmd.Dir = cwd
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return m.run(cwd, os.Stdout, os.Stderr, "pull")
 }
 
 // PullQuiet runs git pull in the given directory with suppressed output.
+// Progress is reported to the callback set with WithProgress, if any.
 func (m *Manager) PullQuiet(cwd string) error {
-	cmd := exec.Command("git", "pull", "--quiet")
-	cmd.Dir = cwd
-	cmd.Stdout = io.Discard
-	cmd.Stderr = io.Discard
-	return cmd.Run()
+	flags, progress := m.progressArgs()
+	return m.run(cwd, nil, stderrOf(progress), append([]string{"pull"}, flags...)...)
 }
 
 // Fetch runs git fetch origin in the given directory.
 func (m *Manager) Fetch(cwd string) error {
-	cmd := exec.Command("git", "fetch", "origin")
-	cmd.Dir = cwd
-	return cmd.Run()
+	return m.run(cwd, nil, nil, "fetch", "origin")
+}
+
+// GC compacts the object database of a local repo.
+func (m *Manager) GC(repoDir string) error {
+	return m.run(repoDir, nil, nil, "gc", "--quiet", "--prune=now")
+}
+
+// ListTreeDirs returns the names of the directories directly under path at rev
+// in a local repo. An empty path lists the repository root.
+func (m *Manager) ListTreeDirs(repoDir, rev, path string) ([]string, error) {
+	out, err := m.output(repoDir, "ls-tree", "-d", "--name-only", rev+":"+path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s:%s in %s: %w", rev, path, repoDir, err)
+	}
+	var dirs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			dirs = append(dirs, line)
+		}
+	}
+	return dirs, nil
+}
+
+// HasPath reports whether path exists at rev in a local repo.
+func (m *Manager) HasPath(repoDir, rev, path string) bool {
+	return m.run(repoDir, nil, nil, "cat-file", "-e", rev+":"+path) == nil
 }
 
 // CheckRemoteSkillMd checks if SKILL.md exists at the given path in a remote repo.
@@ -246,7 +309,7 @@ func (m *Manager) CheckRemoteSkillMd(userRepo, branch, subPath string) bool {
 	}
 
 	// Try git archive first
-	refOut, err := exec.Command("git", "ls-remote", url, "refs/heads/"+branch).Output()
+	refOut, err := m.output("", "ls-remote", url, "refs/heads/"+branch)
 	if err != nil {
 		return false
 	}
@@ -258,7 +321,7 @@ func (m *Manager) CheckRemoteSkillMd(userRepo, branch, subPath string) bool {
 	commitHash := matches[1]
 
 	// Try git archive (may not be supported by all hosts)
-	if err := exec.Command("git", "archive", "--remote", url, commitHash, skillPath).Run(); err == nil {
+	if err := m.run("", nil, nil, "archive", "--remote", url, commitHash, skillPath); err == nil {
 		return true
 	}
 
@@ -271,26 +334,16 @@ func (m *Manager) CheckRemoteSkillMd(userRepo, branch, subPath string) bool {
 		checkPath = subPath
 	}
 
-	cmd := exec.Command("git", "clone", "--depth=1", "--filter=blob:none", "--no-checkout", url, tmpDir)
-	if err := cmd.Run(); err != nil {
+	if err := m.run("", nil, nil, "clone", "--depth=1", "--filter=blob:none", "--no-checkout", url, tmpDir); err != nil {
 		return false
 	}
-
-	cmd = exec.Command("git", "sparse-checkout", "init", "--cone")
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
+	if err := m.run(tmpDir, nil, nil, "sparse-checkout", "init", "--cone"); err != nil {
 		return false
 	}
-
-	cmd = exec.Command("git", "sparse-checkout", "set", checkPath)
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
+	if err := m.run(tmpDir, nil, nil, "sparse-checkout", "set", checkPath); err != nil {
 		return false
 	}
-
-	cmd = exec.Command("git", "checkout", branch)
-	cmd.Dir = tmpDir
-	if err := cmd.Run(); err != nil {
+	if err := m.run(tmpDir, nil, nil, "checkout", branch); err != nil {
 		return false
 	}
 
@@ -303,7 +356,7 @@ func (m *Manager) CheckRemoteSkillMd(userRepo, branch, subPath string) bool {
 func (m *Manager) GetDefaultBranch(userRepo string) string {
 	url := "https://github.com/" + userRepo + ".git"
 
-	out, err := exec.Command("git", "ls-remote", "--symref", url, "HEAD").Output()
+	out, err := m.output("", "ls-remote", "--symref", url, "HEAD")
 	if err == nil {
 		re := regexp.MustCompile(`ref: refs/heads/([^\t\n]+)`)
 		if matches := re.FindStringSubmatch(string(out)); matches != nil {
@@ -313,15 +366,131 @@ func (m *Manager) GetDefaultBranch(userRepo string) string {
 	return "main"
 }
 
+// RemoteURL returns the URL of the origin remote of a local repo.
+func (m *Manager) RemoteURL(repoDir string) (string, error) {
+	out, err := m.output(repoDir, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin URL for %s: %w", repoDir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CurrentBranch returns the branch checked out in a local repo.
+func (m *Manager) CurrentBranch(repoDir string) (string, error) {
+	out, err := m.output(repoDir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch for %s: %w", repoDir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// IsDirty reports whether a local repo has uncommitted or untracked changes.
+func (m *Manager) IsDirty(repoDir string) (bool, error) {
+	out, err := m.output(repoDir, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to get status for %s: %w", repoDir, err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// HooksDir returns the absolute path of the hooks directory of the repository
+// containing dir, honoring core.hooksPath and linked worktrees.
+func (m *Manager) HooksDir(dir string) (string, error) {
+	return m.GitPath(dir, "hooks")
+}
+
+// GitPath returns the absolute path of name inside the git directory of the
+// repository containing dir, e.g. "info/exclude".
+func (m *Manager) GitPath(dir, name string) (string, error) {
+	out, err := m.output(dir, "rev-parse", "--git-path", name)
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	p := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	return p, nil
+}
+
+// TopLevel returns the root of the work tree containing dir.
+func (m *Manager) TopLevel(dir string) (string, error) {
+	out, err := m.output(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("%s is not in a git repository: %w", dir, err)
+	}
+	return filepath.FromSlash(strings.TrimSpace(string(out))), nil
+}
+
+// DefaultRemoteBranch returns the branch origin's HEAD points to, e.g. "main".
+func (m *Manager) DefaultRemoteBranch(repoDir string) (string, error) {
+	out, err := m.output(repoDir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get default branch for %s: %w", repoDir, err)
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/"), nil
+}
+
+// Checkout switches a local repo to branch.
+func (m *Manager) Checkout(repoDir, branch string) error {
+	return m.run(repoDir, nil, nil, "checkout", "--quiet", branch)
+}
+
+// CheckoutAt points branch at commit and checks it out, so later pulls still
+// track the branch. An empty branch detaches HEAD at commit.
+func (m *Manager) CheckoutAt(repoDir, branch, commit string) error {
+	if branch == "" {
+		return m.run(repoDir, nil, nil, "checkout", "--quiet", "--detach", commit)
+	}
+	return m.run(repoDir, nil, nil, "checkout", "--quiet", "-B", branch, commit)
+}
+
+// ExportPath writes the files under path at rev in a local repo to dest,
+// which must not exist, without touching the working tree.
+func (m *Manager) ExportPath(repoDir, rev, path, dest string) error {
+	var archive bytes.Buffer
+	if err := m.run(repoDir, &archive, nil, "archive", "--format=tar", rev+":"+path); err != nil {
+		return fmt.Errorf("failed to export %s at %s: %w", path, rev, err)
+	}
+	return extractTar(&archive, dest)
+}
+
+// Discard resets a local repo to HEAD and deletes untracked files.
+func (m *Manager) Discard(repoDir string) error {
+	if err := m.run(repoDir, nil, nil, "reset", "--hard", "--quiet", "HEAD"); err != nil {
+		return err
+	}
+	return m.run(repoDir, nil, nil, "clean", "-fdq")
+}
+
+// ResolveRef returns the commit hash rev points to in a local repo.
+func (m *Manager) ResolveRef(repoDir, rev string) (string, error) {
+	out, err := m.output(repoDir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s in %s: %w", rev, repoDir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// CountCommits returns the number of commits reachable from to but not from
+// from that touch subPath in a local repo.
+func (m *Manager) CountCommits(repoDir, from, to, subPath string) (int, error) {
+	out, err := m.output(repoDir, "rev-list", "--count", from+".."+to, "--", subPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count commits in %s: %w", repoDir, err)
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
 // GetLocalPathCommitID returns the latest commit hash for a path in a local repo.
 func (m *Manager) GetLocalPathCommitID(repoDir, subPath string) (string, error) {
-	out, err := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%H", "--", subPath).Output()
+	out, err := m.output(repoDir, "log", "-1", "--format=%H", "--", subPath)
 	if err == nil && strings.TrimSpace(string(out)) != "" {
 		return strings.TrimSpace(string(out)), nil
 	}
 
 	// Fallback to HEAD
-	out, err = exec.Command("git", "-C", repoDir, "rev-parse", "HEAD").Output()
+	out, err = m.output(repoDir, "rev-parse", "HEAD")
 	if err != nil {
 		return "", fmt.Errorf("failed to get HEAD for %s: %w", repoDir, err)
 	}
@@ -331,13 +500,13 @@ func (m *Manager) GetLocalPathCommitID(repoDir, subPath string) (string, error)
 // GetRemotePathCommitID returns the latest commit hash for a path on a remote branch.
 // Should be called after Fetch.
 func (m *Manager) GetRemotePathCommitID(repoDir, remoteBranch, subPath string) (string, error) {
-	out, err := exec.Command("git", "-C", repoDir, "log", "-1", "--format=%H", remoteBranch, "--", subPath).Output()
+	out, err := m.output(repoDir, "log", "-1", "--format=%H", remoteBranch, "--", subPath)
 	if err == nil && strings.TrimSpace(string(out)) != "" {
 		return strings.TrimSpace(string(out)), nil
 	}
 
 	// Fallback to remote branch HEAD
-	out, err = exec.Command("git", "-C", repoDir, "rev-parse", remoteBranch).Output()
+	out, err = m.output(repoDir, "rev-parse", remoteBranch)
 	if err != nil {
 		return "", fmt.Errorf("failed to get %s HEAD for %s: %w", remoteBranch, repoDir, err)
 	}
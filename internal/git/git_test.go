@@ -1,6 +1,16 @@
 package git
 
-import "testing"
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestNormalizeURL(t *testing.T) {
 	m := NewManager()
@@ -69,3 +79,149 @@ func TestNormalizeURL(t *testing.T) {
 		})
 	}
 }
+
+func TestFailedCommandIncludesStderrAndTrace(t *testing.T) {
+	var traced strings.Builder
+	SetTrace(&traced)
+	t.Cleanup(func() { SetTrace(nil) })
+
+	dir := t.TempDir()
+	_, err := NewManager().output(dir, "rev-parse", "HEAD")
+	if err == nil {
+		t.Fatal("expected rev-parse outside a repository to fail")
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("error %T is not a *CommandError", err)
+	}
+	if !strings.Contains(err.Error(), "not a git repository") {
+		t.Fatalf("error %q does not include git's stderr", err)
+	}
+	if log := traced.String(); !strings.Contains(log, "git rev-parse HEAD (in "+dir+")") || !strings.Contains(log, "not a git repository") {
+		t.Fatalf("trace missing command or stderr:\n%s", log)
+	}
+}
+
+func TestCommandTimeoutAndCancel(t *testing.T) {
+	SetTimeout(time.Nanosecond)
+	t.Cleanup(func() { SetTimeout(DefaultTimeout) })
+	err := NewManager().run("", nil, nil, "--version")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("run() with a tiny timeout = %v, want a timeout", err)
+	}
+
+	SetTimeout(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewManager().WithContext(ctx).run("", nil, nil, "--version"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("run() after cancel = %v, want context.Canceled", err)
+	}
+}
+
+func TestFailedCloneLeavesNothingBehind(t *testing.T) {
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "skill")
+	if err := NewManager().CloneFullQuiet(filepath.Join(parent, "missing"), dest); err == nil {
+		t.Fatal("expected clone of a missing repository to fail")
+	}
+	if entries, _ := os.ReadDir(parent); len(entries) != 0 {
+		t.Fatalf("failed clone left %v behind", entries)
+	}
+
+	src := filepath.Join(parent, "src")
+	if err := NewManager().run("", nil, nil, "init", "-q", src); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewManager().CloneFullQuiet(src, dest); err != nil {
+		t.Fatalf("CloneFullQuiet() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".git")); err != nil {
+		t.Fatalf("clone not moved into place: %v", err)
+	}
+	if entries, _ := os.ReadDir(parent); len(entries) != 2 {
+		t.Fatalf("expected only src and skill in %s, got %v", parent, entries)
+	}
+}
+
+func TestStagingPID(t *testing.T) {
+	if pid, ok := StagingPID(".github__org__repo.clone-42"); !ok || pid != 42 {
+		t.Fatalf("StagingPID() = %d, %v; want 42, true", pid, ok)
+	}
+	if pid, ok := StagingPID(".review.vendor-7"); !ok || pid != 7 {
+		t.Fatalf("StagingPID() = %d, %v; want 7, true", pid, ok)
+	}
+	if pid, ok := StagingPID(filepath.Base(StagingDir(filepath.Join("repo", "review")))); !ok || pid != os.Getpid() {
+		t.Fatalf("StagingPID() of StagingDir() = %d, %v; want %d, true", pid, ok, os.Getpid())
+	}
+	for _, name := range []string{"github__org__repo", ".hidden", ".x.clone-abc"} {
+		if _, ok := StagingPID(name); ok {
+			t.Errorf("StagingPID(%q) matched", name)
+		}
+	}
+}
+
+func TestExtractTarStaysInDestination(t *testing.T) {
+	archive := func(entries ...tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range entries {
+			if err := tw.WriteHeader(&hdr); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+	parent := t.TempDir()
+
+	for name, links := range map[string]string{"absolute": parent, "relative": "../../outside"} {
+		dest := filepath.Join(parent, name)
+		r := archive(tar.Header{Name: "skill/out", Typeflag: tar.TypeSymlink, Linkname: links})
+		if err := extractTar(r, dest); err == nil {
+			t.Errorf("%s: expected a link out of the destination to be rejected", name)
+		}
+	}
+
+	// Links that stay inside are kept, but nothing is written through them.
+	dest := filepath.Join(parent, "through")
+	r := archive(
+		tar.Header{Name: "skill/", Typeflag: tar.TypeDir, Mode: 0755},
+		tar.Header{Name: "skill/docs", Typeflag: tar.TypeSymlink, Linkname: "."},
+		tar.Header{Name: "skill/docs/x", Typeflag: tar.TypeReg, Mode: 0644},
+	)
+	if err := extractTar(r, dest); err == nil || !strings.Contains(err.Error(), "symlink") {
+		t.Fatalf("expected writing through a link to fail, got %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "skill", "docs")); err != nil || target != "." {
+		t.Fatalf("link inside the destination not created: %q, %v", target, err)
+	}
+}
+
+func TestProgressWriter(t *testing.T) {
+	var got []Progress
+	w := &progressWriter{fn: func(p Progress) { got = append(got, p) }}
+	stream := "Cloning into 'x'...\nremote: Enumerating objects: 12, done.\n" +
+		"Receiving objects:  50% (5/10)\rReceiving obj"
+	w.Write([]byte(stream))
+	w.Write([]byte("ects: 100% (10/10), 1.2 KiB | 1.2 MiB/s, done.\n"))
+
+	want := []Progress{
+		{Phase: "Enumerating objects", Percent: -1, Current: 12},
+		{Phase: "Receiving objects", Percent: 50, Current: 5, Total: 10},
+		{Phase: "Receiving objects", Percent: 100, Current: 10, Total: 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if s := stripProgress("fatal: a\r\nReceiving: 1%\rReceiving: 9%\n"); s != "fatal: a\nReceiving: 9%\n" {
+		t.Errorf("stripProgress() = %q", s)
+	}
+}
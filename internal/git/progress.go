@@ -0,0 +1,100 @@
+package git
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Progress is one update parsed from git's --progress output, such as
+// "Receiving objects:  45% (450/1000)".
+type Progress struct {
+	Phase   string `json:"phase"`
+	Percent int    `json:"percent"` // -1 when git only reports a count
+	Current int    `json:"current"`
+	Total   int    `json:"total,omitempty"`
+}
+
+var (
+	progressPercentRe = regexp.MustCompile(`^(?:remote: )?([A-Za-z][A-Za-z ]*):\s+(\d+)% \((\d+)/(\d+)\)`)
+	progressCountRe   = regexp.MustCompile(`^(?:remote: )?([A-Za-z][A-Za-z ]*):\s+(\d+)(?:,|$)`)
+)
+
+// parseProgress parses one line of git progress output.
+func parseProgress(line string) (Progress, bool) {
+	line = strings.TrimSpace(line)
+	if m := progressPercentRe.FindStringSubmatch(line); m != nil {
+		percent, _ := strconv.Atoi(m[2])
+		current, _ := strconv.Atoi(m[3])
+		total, _ := strconv.Atoi(m[4])
+		return Progress{Phase: m[1], Percent: percent, Current: current, Total: total}, true
+	}
+	if m := progressCountRe.FindStringSubmatch(line); m != nil {
+		current, _ := strconv.Atoi(m[2])
+		return Progress{Phase: m[1], Percent: -1, Current: current}, true
+	}
+	return Progress{}, false
+}
+
+// progressWriter feeds git's stderr to a callback. Git redraws a progress line
+// by ending it with \r rather than \n, so both end an update.
+type progressWriter struct {
+	fn   func(Progress)
+	buf  []byte
+	last Progress
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			return len(p), nil
+		}
+		// Git repeats the final state of a phase with ", done." appended.
+		if prog, ok := parseProgress(string(w.buf[:i])); ok && prog != w.last {
+			w.last = prog
+			w.fn(prog)
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// WithProgress returns a copy of m whose clones and pulls report git's progress to fn.
+// fn is called from the goroutine reading git's output.
+func (m *Manager) WithProgress(fn func(Progress)) *Manager {
+	return &Manager{ctx: m.ctx, progress: fn}
+}
+
+// stderrOf returns w as an io.Writer, or nil (discard) when w is nil.
+func stderrOf(w *progressWriter) io.Writer {
+	if w == nil {
+		return nil
+	}
+	return w
+}
+
+// stripProgress drops redrawn progress from captured stderr, keeping only the
+// final state of each line.
+func stripProgress(s string) string {
+	if !strings.Contains(s, "\r") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		l = strings.TrimRight(l, "\r")
+		lines[i] = l[strings.LastIndex(l, "\r")+1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// progressArgs returns the flag and stderr writer for a quiet command that
+// should still report progress.
+func (m *Manager) progressArgs() ([]string, *progressWriter) {
+	if m.progress == nil {
+		return []string{"--quiet"}, nil
+	}
+	return []string{"--progress"}, &progressWriter{fn: m.progress}
+}
@@ -0,0 +1,313 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+// Change kinds recorded in an entry.
+const (
+	SkillAdded    = "skill-added"    // the skill did not exist before
+	SkillReplaced = "skill-replaced" // the skill's previous files were overwritten
+	SkillRemoved  = "skill-removed"  // the skill was deleted
+	LinkCreated   = "link-created"   // a link was added to a project
+	LinkRemoved   = "link-removed"   // a link was removed from a project
+	CopyCreated   = "copy-created"   // a skill was copied into a project
+	CopyRemoved   = "copy-removed"   // a skill copy was removed from a project
+	CheckedOut    = "checked-out"    // a skill's clone was moved to another commit
+)
+
+// Change is one reversible step of an operation.
+type Change struct {
+	Kind     string        `json:"kind"`
+	SkillID  string        `json:"skillId,omitempty"`
+	Previous *skills.Skill `json:"previous,omitempty"` // registry entry before the change
+	Backup   string        `json:"backup,omitempty"`   // backup of the repo directory, relative to the journal directory
+	Path     string        `json:"path,omitempty"`     // link or copy path
+	Target   string        `json:"target,omitempty"`   // link target
+	Branch   string        `json:"branch,omitempty"`   // branch a clone was on; empty when detached
+	Commit   string        `json:"commit,omitempty"`   // commit a clone was on
+}
+
+// Entry is one recorded operation. Undo entries set Undoes to the ID of the
+// entry they reversed and carry no changes of their own.
+type Entry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Op      string    `json:"op"`
+	Undoes  int       `json:"undoes,omitempty"`
+	Changes []Change  `json:"changes"`
+}
+
+// Journal is the append-only log of mutating operations in the agm home directory.
+type Journal struct {
+	dir string
+}
+
+// New returns the journal stored under the config manager's home directory.
+func New(cm *config.Manager) *Journal {
+	return &Journal{dir: filepath.Join(cm.GetHomeDir(), "journal")}
+}
+
+func (j *Journal) file() string {
+	return filepath.Join(j.dir, "journal.jsonl")
+}
+
+// BackupPath returns the absolute path of a change's backup directory.
+func (j *Journal) BackupPath(c Change) string {
+	return filepath.Join(j.dir, c.Backup)
+}
+
+// Entries returns every recorded entry, oldest first.
+func (j *Journal) Entries() ([]Entry, error) {
+	f, err := os.Open(j.file())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("corrupt journal entry after #%d: %w", len(entries), err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Append writes e to the journal, assigning the next ID and the current time.
+func (j *Journal) Append(e Entry) (Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return e, err
+	}
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+	e.Time = time.Now().UTC()
+	if e.Changes == nil {
+		e.Changes = []Change{}
+	}
+
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return e, err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(j.file(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return e, err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return e, err
+	}
+	return e, f.Close()
+}
+
+// Undone returns the IDs of entries that have been reversed by an undo entry.
+func Undone(entries []Entry) map[int]bool {
+	undone := make(map[int]bool)
+	for _, e := range entries {
+		if e.Undoes != 0 {
+			undone[e.Undoes] = true
+		}
+	}
+	return undone
+}
+
+// Op collects the changes of one operation until it is committed.
+// A nil *Op records nothing, so callers need not check for it.
+type Op struct {
+	j       *Journal
+	name    string
+	changes []Change
+}
+
+// Begin starts recording an operation.
+func (j *Journal) Begin(name string) *Op {
+	return &Op{j: j, name: name}
+}
+
+// Commit appends the operation to the journal if it changed anything.
+func (o *Op) Commit() error {
+	if o == nil || len(o.changes) == 0 {
+		return nil
+	}
+	_, err := o.j.Append(Entry{Op: o.name, Changes: o.changes})
+	o.changes = nil
+	return err
+}
+
+// SkillAdded records that a skill which did not exist was installed.
+func (o *Op) SkillAdded(id string) {
+	if o == nil {
+		return
+	}
+	o.changes = append(o.changes, Change{Kind: SkillAdded, SkillID: id})
+}
+
+// SkillReplaced moves a skill's repo directory into the journal before it is
+// overwritten. Without an Op the directory is simply deleted.
+func (o *Op) SkillReplaced(prev skills.Skill, repoPath string) error {
+	return o.backupSkill(SkillReplaced, prev, repoPath)
+}
+
+// SkillRemoved moves a skill's repo directory into the journal as it is
+// deleted. Without an Op the directory is simply deleted.
+func (o *Op) SkillRemoved(prev skills.Skill, repoPath string) error {
+	return o.backupSkill(SkillRemoved, prev, repoPath)
+}
+
+func (o *Op) backupSkill(kind string, prev skills.Skill, repoPath string) error {
+	if o == nil {
+		return os.RemoveAll(repoPath)
+	}
+	change := Change{Kind: kind, SkillID: prev.ID, Previous: &prev}
+	backup, err := o.backup(prev.ID, repoPath)
+	if err != nil {
+		return err
+	}
+	change.Backup = backup
+	o.changes = append(o.changes, change)
+	return nil
+}
+
+// backup moves path into the journal's backups and returns its location
+// relative to the journal directory, or "" if path does not exist.
+func (o *Op) backup(id, path string) (string, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return "", nil
+	}
+	backup := filepath.Join("backups", fmt.Sprintf("%d", time.Now().UnixNano()), filepath.Base(path))
+	dest := filepath.Join(o.j.dir, backup)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", err
+	}
+	if err := Move(path, dest); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", id, err)
+	}
+	return backup, nil
+}
+
+// rename is os.Rename; tests replace it to simulate other file systems.
+var rename = os.Rename
+
+// Move renames src to dst. Projects are often on another file system than the
+// journal, e.g. a bind mount or a second disk, where rename fails with EXDEV;
+// src is then copied to dst and removed.
+func Move(src, dst string) error {
+	err := rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree copies the file, symlink or directory tree at src to dst, keeping
+// symlinks and file modes.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(dest, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dest)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dest, data, info.Mode().Perm())
+	})
+}
+
+// LinkCreated records a new link to a skill in a project.
+func (o *Op) LinkCreated(skillID, path, target string) {
+	if o == nil {
+		return
+	}
+	o.changes = append(o.changes, Change{Kind: LinkCreated, SkillID: skillID, Path: path, Target: target})
+}
+
+// LinkRemoved records a link to a skill removed from a project.
+func (o *Op) LinkRemoved(skillID, path, target string) {
+	if o == nil {
+		return
+	}
+	o.changes = append(o.changes, Change{Kind: LinkRemoved, SkillID: skillID, Path: path, Target: target})
+}
+
+// CheckedOut records that the clone of prev at repoDir was moved away from
+// commit on branch.
+func (o *Op) CheckedOut(prev skills.Skill, repoDir, branch, commit string) {
+	if o == nil {
+		return
+	}
+	o.changes = append(o.changes, Change{Kind: CheckedOut, SkillID: prev.ID, Previous: &prev, Path: repoDir, Branch: branch, Commit: commit})
+}
+
+// CopyCreated records a skill copied into a project at path.
+func (o *Op) CopyCreated(skillID, path string) {
+	if o == nil {
+		return
+	}
+	o.changes = append(o.changes, Change{Kind: CopyCreated, SkillID: skillID, Path: path})
+}
+
+// CopyRemoved moves a skill copy in a project into the journal as it is
+// deleted. Without an Op the copy is simply deleted.
+func (o *Op) CopyRemoved(skillID, path string) error {
+	if o == nil {
+		return os.RemoveAll(path)
+	}
+	backup, err := o.backup(skillID, path)
+	if err != nil {
+		return err
+	}
+	o.changes = append(o.changes, Change{Kind: CopyRemoved, SkillID: skillID, Path: path, Backup: backup})
+	return nil
+}
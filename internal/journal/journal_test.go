@@ -0,0 +1,111 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/skills"
+)
+
+func TestOpCommitAppendsEntries(t *testing.T) {
+	t.Parallel()
+
+	j := &Journal{dir: t.TempDir()}
+
+	empty := j.Begin("link")
+	if err := empty.Commit(); err != nil {
+		t.Fatalf("Commit() of empty op failed: %v", err)
+	}
+
+	op := j.Begin("link")
+	op.LinkCreated("local:a", "/p/.claude/skills/a", "/repo/local__a")
+	if err := op.Commit(); err != nil {
+		t.Fatalf("Commit() failed: %v", err)
+	}
+	if _, err := j.Append(Entry{Op: "undo", Undoes: 1}); err != nil {
+		t.Fatalf("Append() failed: %v", err)
+	}
+
+	entries, err := j.Entries()
+	if err != nil {
+		t.Fatalf("Entries() failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2 (empty ops are not recorded)", len(entries))
+	}
+	if entries[0].ID != 1 || entries[0].Op != "link" || len(entries[0].Changes) != 1 {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].ID != 2 || !Undone(entries)[1] {
+		t.Fatalf("expected entry 2 to undo entry 1, got %+v", entries[1])
+	}
+}
+
+func TestSkillRemovedMovesRepoIntoBackup(t *testing.T) {
+	t.Parallel()
+
+	j := &Journal{dir: t.TempDir()}
+	repoPath := filepath.Join(t.TempDir(), "local__a")
+	if err := os.MkdirAll(repoPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoPath, "SKILL.md"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	op := j.Begin("delete")
+	if err := op.SkillRemoved(skills.Skill{ID: "local:a", Type: "local"}, repoPath); err != nil {
+		t.Fatalf("SkillRemoved() failed: %v", err)
+	}
+	if _, err := os.Stat(repoPath); !os.IsNotExist(err) {
+		t.Fatalf("expected repo directory to be moved, stat err: %v", err)
+	}
+	change := op.changes[0]
+	if change.Previous == nil || change.Previous.ID != "local:a" {
+		t.Fatalf("previous entry not recorded: %+v", change)
+	}
+	if _, err := os.Stat(filepath.Join(j.BackupPath(change), "SKILL.md")); err != nil {
+		t.Fatalf("backup missing: %v", err)
+	}
+
+	var none *Op
+	if err := none.SkillRemoved(skills.Skill{ID: "local:b"}, filepath.Join(t.TempDir(), "gone")); err != nil {
+		t.Fatalf("nil Op SkillRemoved() failed: %v", err)
+	}
+}
+
+func TestBackupCopiesAcrossFileSystems(t *testing.T) {
+	rename = func(string, string) error {
+		return &os.LinkError{Op: "rename", Err: syscall.EXDEV}
+	}
+	t.Cleanup(func() { rename = os.Rename })
+
+	j := &Journal{dir: t.TempDir()}
+	copyPath := filepath.Join(t.TempDir(), "a")
+	if err := os.MkdirAll(filepath.Join(copyPath, "scripts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(copyPath, "scripts", "run.sh"), []byte("run"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("scripts/run.sh", filepath.Join(copyPath, "run")); err != nil {
+		t.Fatal(err)
+	}
+
+	op := j.Begin("unlink")
+	if err := op.CopyRemoved("local:a", copyPath); err != nil {
+		t.Fatalf("CopyRemoved() failed: %v", err)
+	}
+	if _, err := os.Lstat(copyPath); !os.IsNotExist(err) {
+		t.Fatalf("%s still exists after CopyRemoved()", copyPath)
+	}
+	backup := j.BackupPath(op.changes[0])
+	if info, err := os.Stat(filepath.Join(backup, "scripts", "run.sh")); err != nil || info.Mode().Perm() != 0o755 {
+		t.Fatalf("backed-up file = %v, %v", info, err)
+	}
+	if target, err := os.Readlink(filepath.Join(backup, "run")); err != nil || target != "scripts/run.sh" {
+		t.Fatalf("backed-up symlink = %q, %v", target, err)
+	}
+}
@@ -0,0 +1,183 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// LockFileName is the lockfile's name in the project root.
+const LockFileName = "agm.lock"
+
+// LockVersion is bumped whenever the lockfile format changes incompatibly.
+const LockVersion = 1
+
+// Lock pins the version of every skill in a project's manifest.
+type Lock struct {
+	Version int           `json:"lockfileVersion"`
+	Skills  []LockedSkill `json:"skills"`
+
+	path string
+}
+
+// LockedSkill is the exact version of one skill.
+type LockedSkill struct {
+	ID     string `json:"id"`
+	Source string `json:"source,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	// Commit is the last commit touching the skill's directory; empty for
+	// local skills.
+	Commit string `json:"commit,omitempty"`
+	// Hash is the HashDir of the skill's directory.
+	Hash string `json:"hash"`
+}
+
+// LockPath returns the lockfile path for the project in dir.
+func LockPath(dir string) string {
+	return filepath.Join(dir, LockFileName)
+}
+
+// LoadLock reads the lockfile of the project in dir. A missing lockfile
+// yields an empty one; use Exists to tell the two apart.
+func LoadLock(dir string) (*Lock, error) {
+	l := &Lock{Version: LockVersion, path: LockPath(dir)}
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", l.path, err)
+	}
+	if l.Version > LockVersion {
+		return nil, fmt.Errorf("%s has lockfile version %d; this agm understands up to %d", l.path, l.Version, LockVersion)
+	}
+	return l, nil
+}
+
+// Exists reports whether the lockfile exists.
+func (l *Lock) Exists() bool {
+	_, err := os.Stat(l.path)
+	return err == nil
+}
+
+// Save writes the lockfile with skills sorted by ID.
+func (l *Lock) Save() error {
+	sort.Slice(l.Skills, func(i, j int) bool { return l.Skills[i].ID < l.Skills[j].ID })
+	if l.Skills == nil {
+		l.Skills = []LockedSkill{}
+	}
+	l.Version = LockVersion
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, append(data, '\n'), 0644)
+}
+
+// Get returns the locked version of skill id, or nil.
+func (l *Lock) Get(id string) *LockedSkill {
+	for i := range l.Skills {
+		if l.Skills[i].ID == id {
+			return &l.Skills[i]
+		}
+	}
+	return nil
+}
+
+// Set records s, replacing any entry for the same skill. An empty branch keeps
+// the recorded one, since a pinned clone is not always on a branch. It reports
+// whether the lockfile changed.
+func (l *Lock) Set(s LockedSkill) bool {
+	prev := l.Get(s.ID)
+	if prev == nil {
+		l.Skills = append(l.Skills, s)
+		return true
+	}
+	if s.Branch == "" {
+		s.Branch = prev.Branch
+	}
+	if *prev == s {
+		return false
+	}
+	*prev = s
+	return true
+}
+
+// Remove drops skill id. It reports whether the lockfile changed.
+func (l *Lock) Remove(id string) bool {
+	i := slices.IndexFunc(l.Skills, func(s LockedSkill) bool { return s.ID == id })
+	if i < 0 {
+		return false
+	}
+	l.Skills = slices.Delete(l.Skills, i, i+1)
+	return true
+}
+
+// HashDir returns a hash of the files under dir: their paths, executable bits,
+// symlink targets and contents. .git directories and the provenance marker of
+// a copy are skipped, so a skill hashes the same whether it was cloned or
+// copied.
+func HashDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ProvenanceFileName {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "link %s\x00%s\x00", rel, filepath.ToSlash(target))
+		case info.Mode().IsRegular():
+			kind := "file"
+			if info.Mode()&0111 != 0 {
+				kind = "exec"
+			}
+			fmt.Fprintf(h, "%s %s\x00%d\x00", kind, rel, info.Size())
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(h, f)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
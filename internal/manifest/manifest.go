@@ -0,0 +1,134 @@
+// Package manifest reads and writes a project's .agm.json, which declares the
+// skills the project uses and the AI tools each one is linked into, and its
+// agm.lock, which pins the exact version of each skill.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// FileName is the manifest's name in the project root.
+const FileName = ".agm.json"
+
+// Manifest is the list of skills a project uses.
+type Manifest struct {
+	// Registry is the registry URL that registry: skills come from.
+	Registry string `json:"registry,omitempty"`
+	// IgnoreLinks overrides the global ignoreLinks setting for the project:
+	// "gitignore", "exclude" or "off".
+	IgnoreLinks string `json:"ignoreLinks,omitempty"`
+	// LinkStrategy overrides the global linkStrategy setting for the project:
+	// "absolute", "relative" or "home".
+	LinkStrategy string  `json:"linkStrategy,omitempty"`
+	Skills       []Skill `json:"skills"`
+
+	path string
+}
+
+// Skill is one skill in the manifest.
+type Skill struct {
+	ID string `json:"id"`
+	// Source is where the skill is imported from: a GitHub URL for github:
+	// skills and a folder, relative to the project root when inside it, for
+	// local: skills. Registry skills come from the manifest's registry.
+	Source string   `json:"source,omitempty"`
+	Tools  []string `json:"tools"`
+}
+
+// Path returns the manifest path for the project in dir.
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads the manifest of the project in dir. A missing manifest yields an
+// empty one; use Exists to tell the two apart.
+func Load(dir string) (*Manifest, error) {
+	m := &Manifest{path: Path(dir)}
+	data, err := os.ReadFile(m.path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", m.path, err)
+	}
+	return m, nil
+}
+
+// Exists reports whether the manifest file exists.
+func (m *Manifest) Exists() bool {
+	_, err := os.Stat(m.path)
+	return err == nil
+}
+
+// Save writes the manifest with skills sorted by ID.
+func (m *Manifest) Save() error {
+	sort.Slice(m.Skills, func(i, j int) bool { return m.Skills[i].ID < m.Skills[j].ID })
+	if m.Skills == nil {
+		m.Skills = []Skill{}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(m.path, append(data, '\n'), 0644)
+}
+
+// Get returns the skill with the given ID, or nil.
+func (m *Manifest) Get(id string) *Skill {
+	for i := range m.Skills {
+		if m.Skills[i].ID == id {
+			return &m.Skills[i]
+		}
+	}
+	return nil
+}
+
+// Link records that skill id from source is linked into tool. An empty source
+// keeps the recorded one. It reports whether the manifest changed.
+func (m *Manifest) Link(id, source, tool string) bool {
+	s := m.Get(id)
+	if s == nil {
+		m.Skills = append(m.Skills, Skill{ID: id})
+		s = &m.Skills[len(m.Skills)-1]
+	}
+	changed := false
+	if source != "" && s.Source != source {
+		s.Source = source
+		changed = true
+	}
+	if !slices.Contains(s.Tools, tool) {
+		s.Tools = append(s.Tools, tool)
+		slices.Sort(s.Tools)
+		changed = true
+	}
+	return changed
+}
+
+// Unlink records that skill id is no longer linked into tool, dropping the
+// skill once no tool uses it. It reports whether the manifest changed.
+func (m *Manifest) Unlink(id, tool string) bool {
+	for i := range m.Skills {
+		s := &m.Skills[i]
+		if s.ID != id {
+			continue
+		}
+		j := slices.Index(s.Tools, tool)
+		if j < 0 {
+			return false
+		}
+		s.Tools = slices.Delete(s.Tools, j, j+1)
+		if len(s.Tools) == 0 {
+			m.Skills = slices.Delete(m.Skills, i, i+1)
+		}
+		return true
+	}
+	return false
+}
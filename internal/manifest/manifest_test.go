@@ -0,0 +1,96 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLinkUnlinkRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	m, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() of a missing manifest failed: %v", err)
+	}
+	if m.Exists() {
+		t.Fatal("expected no manifest yet")
+	}
+
+	m.Registry = "https://github.com/org/skills"
+	m.Link("registry:review", "", "cursor")
+	m.Link("registry:review", "", "claude")
+	if m.Link("registry:review", "", "claude") {
+		t.Fatal("linking twice reported a change")
+	}
+	m.Link("local:notes", "skills/notes", "claude")
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	m, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if !m.Exists() || m.Registry != "https://github.com/org/skills" || len(m.Skills) != 2 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if m.Skills[0].ID != "local:notes" || m.Skills[0].Source != "skills/notes" {
+		t.Fatalf("skills not sorted or source lost: %+v", m.Skills)
+	}
+	if got := m.Get("registry:review").Tools; !slices.Equal(got, []string{"claude", "cursor"}) {
+		t.Fatalf("tools = %v", got)
+	}
+
+	m.Unlink("registry:review", "cursor")
+	m.Unlink("local:notes", "claude")
+	if len(m.Skills) != 1 || !slices.Equal(m.Skills[0].Tools, []string{"claude"}) {
+		t.Fatalf("after unlink: %+v", m.Skills)
+	}
+}
+
+func TestLockAndHashDir(t *testing.T) {
+	t.Parallel()
+
+	skill := t.TempDir()
+	if err := os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(skill, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	first, err := HashDir(skill)
+	if err != nil {
+		t.Fatalf("HashDir() failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(skill, ".git", "HEAD"), []byte("ignored"), 0644)
+	if again, _ := HashDir(skill); again != first {
+		t.Fatal("hash changed with .git contents")
+	}
+	os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte("v2"), 0644)
+	if changed, _ := HashDir(skill); changed == first {
+		t.Fatal("hash did not change with the content")
+	}
+
+	dir := t.TempDir()
+	l, err := LoadLock(dir)
+	if err != nil || l.Exists() {
+		t.Fatalf("LoadLock() of a missing lockfile = %v, %v", l, err)
+	}
+	l.Set(LockedSkill{ID: "registry:review", Branch: "main", Commit: "abc", Hash: first})
+	if !l.Set(LockedSkill{ID: "registry:review", Commit: "def", Hash: first}) {
+		t.Fatal("Set() with a new commit reported no change")
+	}
+	if err := l.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	l, err = LoadLock(dir)
+	if err != nil {
+		t.Fatalf("LoadLock() failed: %v", err)
+	}
+	if s := l.Get("registry:review"); s == nil || s.Branch != "main" || s.Commit != "def" {
+		t.Fatalf("locked skill = %+v, want branch kept and commit updated", s)
+	}
+}
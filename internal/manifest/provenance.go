@@ -0,0 +1,54 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProvenanceFileName marks a skill directory that agm copied into a project
+// instead of linking it.
+const ProvenanceFileName = ".agm-skill.json"
+
+// Provenance records where a skill copy came from.
+type Provenance struct {
+	ID     string `json:"id"`
+	Source string `json:"source,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	// Hash is the HashDir of the copy as agm wrote it; a copy that no longer
+	// hashes to it was edited since.
+	Hash string `json:"hash"`
+	// LinkMode is the link mode of the tool the copy was installed for, "copy" or
+	// "hardlink". It is empty for copies made by agm vendor, which agm only
+	// refreshes when asked.
+	LinkMode string `json:"linkMode,omitempty"`
+}
+
+// ReadProvenance returns the provenance marker in dir, or nil if dir is not a
+// skill copy.
+func ReadProvenance(dir string) (*Provenance, error) {
+	path := filepath.Join(dir, ProvenanceFileName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Provenance
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// WriteProvenance writes p as the provenance marker of the copy in dir.
+func WriteProvenance(dir string, p Provenance) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ProvenanceFileName), append(data, '\n'), 0644)
+}
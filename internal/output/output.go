@@ -0,0 +1,59 @@
+// Package output renders machine-readable command results.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion is bumped whenever a JSON document changes incompatibly.
+// Adding fields is not an incompatible change.
+const SchemaVersion = 1
+
+// Supported --output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Document is the versioned envelope around every JSON result.
+type Document struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Kind          string `json:"kind"`
+	Data          any    `json:"data"`
+}
+
+// ParseFormat validates an --output value.
+func ParseFormat(s string) (string, error) {
+	switch s {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (want text or json)", s)
+	}
+}
+
+// WriteJSON writes data wrapped in a Document of the given kind as indented JSON.
+func WriteJSON(w io.Writer, kind string, data any) error {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Kind:          kind,
+		Data:          data,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// WriteJSONLine writes data wrapped in a Document of the given kind as a single
+// line, for streams of events such as progress updates.
+func WriteJSONLine(w io.Writer, kind string, data any) error {
+	return json.NewEncoder(w).Encode(Document{
+		SchemaVersion: SchemaVersion,
+		Kind:          kind,
+		Data:          data,
+	})
+}
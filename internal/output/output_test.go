@@ -0,0 +1,53 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteJSONEnvelope(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, "skills", map[string]int{"count": 2}); err != nil {
+		t.Fatalf("WriteJSON() returned error: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion int            `json:"schemaVersion"`
+		Kind          string         `json:"kind"`
+		Data          map[string]int `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.SchemaVersion != SchemaVersion || doc.Kind != "skills" || doc.Data["count"] != 2 {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: FormatText},
+		{in: "text", want: FormatText},
+		{in: "json", want: FormatJSON},
+		{in: "yaml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseFormat(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -12,6 +12,8 @@ type Info struct {
 	Type     string
 	Root     string
 	SkillDir string
+	// LinkMode is the tool's config.LinkMode; empty means symlinks.
+	LinkMode string
 }
 
 // Detector auto-detects AI tool project types in a directory.
@@ -63,6 +65,7 @@ func (d *Detector) DetectAll() []Info {
 					Type:     tool.Type,
 					Root:     d.cwd,
 					SkillDir: fullSkillDir,
+					LinkMode: tool.LinkMode,
 				})
 				break // found one for this tool, move on
 			}
@@ -72,6 +75,30 @@ func (d *Detector) DetectAll() []Info {
 	return projects
 }
 
+// Tools returns the AI tool configurations the detector checks.
+func (d *Detector) Tools() []config.AIToolConfig {
+	return d.aiTools
+}
+
+// ForTool returns project information for the named tool type, whether or not
+// the tool is detected. An existing skill directory parent is preferred;
+// otherwise the tool's first skill directory is used.
+func (d *Detector) ForTool(toolType string) (Info, bool) {
+	for _, tool := range d.aiTools {
+		if tool.Type != toolType || len(tool.SkillDirs) == 0 {
+			continue
+		}
+		for _, skillDir := range tool.SkillDirs {
+			fullSkillDir := filepath.Join(d.cwd, skillDir)
+			if _, err := os.Stat(filepath.Dir(fullSkillDir)); err == nil {
+				return Info{Type: tool.Type, Root: d.cwd, SkillDir: fullSkillDir, LinkMode: tool.LinkMode}, true
+			}
+		}
+		return Info{Type: tool.Type, Root: d.cwd, SkillDir: filepath.Join(d.cwd, tool.SkillDirs[0]), LinkMode: tool.LinkMode}, true
+	}
+	return Info{}, false
+}
+
 // Detect returns the first detected AI project type (backward compat).
 func (d *Detector) Detect() Info {
 	projects := d.DetectAll()
@@ -0,0 +1,41 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ArdentaCorp/agent-management/internal/config"
+)
+
+func TestForToolPrefersDetectedSkillDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".agents"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	d := &Detector{cwd: root, aiTools: []config.AIToolConfig{
+		{Type: "codex", SkillDirs: []string{".codex/skills", ".agents/skills"}},
+	}}
+
+	info, ok := d.ForTool("codex")
+	if !ok {
+		t.Fatal("expected codex to be known")
+	}
+	if want := filepath.Join(root, ".agents/skills"); info.SkillDir != want {
+		t.Fatalf("SkillDir = %s, want %s", info.SkillDir, want)
+	}
+
+	if err := os.RemoveAll(filepath.Join(root, ".agents")); err != nil {
+		t.Fatal(err)
+	}
+	info, _ = d.ForTool("codex")
+	if want := filepath.Join(root, ".codex/skills"); info.SkillDir != want {
+		t.Fatalf("undetected SkillDir = %s, want %s", info.SkillDir, want)
+	}
+
+	if _, ok := d.ForTool("unknown"); ok {
+		t.Fatal("expected unknown tool to be rejected")
+	}
+}
@@ -0,0 +1,76 @@
+package skills
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Metadata is the frontmatter at the top of a SKILL.md file.
+type Metadata struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	License     string `json:"license,omitempty"`
+}
+
+// ReadMetadata parses the frontmatter of the SKILL.md in skillDir.
+func ReadMetadata(skillDir string) (Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil {
+		return Metadata{}, err
+	}
+	return ParseMetadata(data), nil
+}
+
+// ParseMetadata extracts name, description and license from a "---" delimited
+// YAML frontmatter block. Only top-level scalar keys are read, including
+// "|" and ">" block scalars; anything else is ignored.
+func ParseMetadata(data []byte) Metadata {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return Metadata{}
+	}
+
+	values := make(map[string]string)
+	for i := 1; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if style := strings.TrimRight(value, "+-"); style == "|" || style == ">" {
+			var block []string
+			for i+1 < len(lines) && (lines[i+1] == "" || lines[i+1][0] == ' ' || lines[i+1][0] == '\t') {
+				i++
+				block = append(block, strings.TrimSpace(lines[i]))
+			}
+			sep := "\n"
+			if style == ">" {
+				sep = " "
+			}
+			value = strings.TrimSpace(strings.Join(block, sep))
+		}
+		values[strings.TrimSpace(key)] = unquote(value)
+	}
+
+	return Metadata{
+		Name:        values["name"],
+		Description: values["description"],
+		License:     values["license"],
+	}
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
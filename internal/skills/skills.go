@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/ArdentaCorp/agent-management/internal/config"
@@ -15,13 +16,20 @@ type Skill struct {
 	CommitID string `json:"commitId,omitempty"`
 	Type     string `json:"type"`
 	Path     string `json:"path,omitempty"`
+	// Source is the folder a local skill was copied from.
+	Source string `json:"source,omitempty"`
+	// Copies are the project skill directories holding a copy of the skill,
+	// made for tools that do not follow symlinks.
+	Copies []string `json:"copies,omitempty"`
 }
 
 // storedSkill is the JSON storage format (without ID, since ID is the map key).
 type storedSkill struct {
-	CommitID string `json:"commitId,omitempty"`
-	Type     string `json:"type"`
-	Path     string `json:"path,omitempty"`
+	CommitID string   `json:"commitId,omitempty"`
+	Type     string   `json:"type"`
+	Path     string   `json:"path,omitempty"`
+	Source   string   `json:"source,omitempty"`
+	Copies   []string `json:"copies,omitempty"`
 }
 
 // Registry manages the skills.json registry file.
@@ -48,15 +56,30 @@ func (r *Registry) load() map[string]storedSkill {
 	return skills
 }
 
+// Validate returns an error if skills.json exists but cannot be read or parsed.
+// Other methods treat an unreadable file as an empty registry.
+func (r *Registry) Validate() error {
+	data, err := os.ReadFile(r.versionsFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var skills map[string]storedSkill
+	return json.Unmarshal(data, &skills)
+}
+
 func (r *Registry) save(skills map[string]storedSkill) {
 	data, _ := json.MarshalIndent(skills, "", "  ")
 	os.WriteFile(r.versionsFile, data, 0644)
 }
 
-// AddSkill registers a new skill.
+// AddSkill registers a new skill. The copies of a skill that is already
+// registered are kept.
 func (r *Registry) AddSkill(id, skillType, commitID, skillPath string) {
 	skills := r.load()
-	s := storedSkill{Type: skillType}
+	s := storedSkill{Type: skillType, Copies: skills[id].Copies}
 	if commitID != "" {
 		s.CommitID = commitID
 	}
@@ -67,6 +90,20 @@ func (r *Registry) AddSkill(id, skillType, commitID, skillPath string) {
 	r.save(skills)
 }
 
+// RestoreSkill registers s exactly as recorded, replacing any entry with
+// its ID.
+func (r *Registry) RestoreSkill(s Skill) {
+	skills := r.load()
+	skills[s.ID] = storedSkill{
+		CommitID: s.CommitID,
+		Type:     s.Type,
+		Path:     s.Path,
+		Source:   s.Source,
+		Copies:   s.Copies,
+	}
+	r.save(skills)
+}
+
 // RemoveSkill removes a skill from the registry.
 func (r *Registry) RemoveSkill(id string) {
 	skills := r.load()
@@ -86,6 +123,8 @@ func (r *Registry) GetSkill(id string) *Skill {
 		CommitID: stored.CommitID,
 		Type:     stored.Type,
 		Path:     stored.Path,
+		Source:   stored.Source,
+		Copies:   stored.Copies,
 	}
 }
 
@@ -99,6 +138,37 @@ func (r *Registry) UpdateSkillVersion(id, newCommitID string) {
 	}
 }
 
+// SetSource records the folder a local skill was copied from.
+func (r *Registry) SetSource(id, source string) {
+	skills := r.load()
+	if s, ok := skills[id]; ok {
+		s.Source = source
+		skills[id] = s
+		r.save(skills)
+	}
+}
+
+// AddCopy records that dir holds a copy of a skill.
+func (r *Registry) AddCopy(id, dir string) {
+	skills := r.load()
+	if s, ok := skills[id]; ok && !slices.Contains(s.Copies, dir) {
+		s.Copies = append(s.Copies, dir)
+		sort.Strings(s.Copies)
+		skills[id] = s
+		r.save(skills)
+	}
+}
+
+// RemoveCopy forgets a copy recorded by AddCopy.
+func (r *Registry) RemoveCopy(id, dir string) {
+	skills := r.load()
+	if s, ok := skills[id]; ok && slices.Contains(s.Copies, dir) {
+		s.Copies = slices.DeleteFunc(s.Copies, func(c string) bool { return c == dir })
+		skills[id] = s
+		r.save(skills)
+	}
+}
+
 // GetAllSkills returns all registered skills, sorted by ID.
 func (r *Registry) GetAllSkills() []Skill {
 	skills := r.load()
@@ -109,6 +179,8 @@ func (r *Registry) GetAllSkills() []Skill {
 			CommitID: stored.CommitID,
 			Type:     stored.Type,
 			Path:     stored.Path,
+			Source:   stored.Source,
+			Copies:   stored.Copies,
 		})
 	}
 	sort.Slice(result, func(i, j int) bool {
@@ -46,3 +46,36 @@ func TestRegistryCRUD(t *testing.T) {
 		t.Fatalf("expected local skill to be removed, got %+v", got)
 	}
 }
+
+func TestParseMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want Metadata
+	}{
+		{
+			name: "plain and quoted values",
+			in:   "---\nname: code-review\ndescription: \"Review: diffs\"\nlicense: 'MIT'\n---\n# Body\nname: ignored\n",
+			want: Metadata{Name: "code-review", Description: "Review: diffs", License: "MIT"},
+		},
+		{
+			name: "folded block description",
+			in:   "---\nname: testing\ndescription: >\n  Writes tests\n  for Go code\nmetadata:\n  owner: qa\n---\n",
+			want: Metadata{Name: "testing", Description: "Writes tests for Go code"},
+		},
+		{
+			name: "no frontmatter",
+			in:   "# Just markdown\nname: nope\n",
+			want: Metadata{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMetadata([]byte(tt.in)); got != tt.want {
+				t.Fatalf("ParseMetadata() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const progressBarWidth = 24
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Progress keeps a status line, such as a progress bar, at the bottom of a
+// terminal. Output written through it is printed above the status line.
+// It is safe for concurrent use.
+type Progress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	line  string
+	frame int
+}
+
+// NewProgress returns a Progress drawing on w, which should be a terminal.
+func NewProgress(w io.Writer) *Progress {
+	return &Progress{w: w}
+}
+
+// Write prints b above the status line.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line == "" {
+		return p.w.Write(b)
+	}
+	fmt.Fprint(p.w, "\r\033[K")
+	n, err := p.w.Write(b)
+	if len(b) > 0 && b[len(b)-1] == '\n' {
+		fmt.Fprint(p.w, p.line)
+	} else {
+		// A partial line; the status line comes back with the next update.
+		p.line = ""
+	}
+	return n, err
+}
+
+// Set replaces the status line with label and a bar filled to percent, or a
+// spinner when percent is negative.
+func (p *Progress) Set(label string, percent int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.line = p.render(label, percent)
+	fmt.Fprint(p.w, "\r\033[K"+p.line)
+}
+
+// Done removes the status line.
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.line != "" {
+		fmt.Fprint(p.w, "\r\033[K")
+		p.line = ""
+	}
+}
+
+func (p *Progress) render(label string, percent int) string {
+	if percent < 0 {
+		p.frame = (p.frame + 1) % len(spinnerFrames)
+		return Subtitle.Render(spinnerFrames[p.frame]) + " " + label
+	}
+	percent = min(percent, 100)
+	filled := progressBarWidth * percent / 100
+	bar := renderBar(filled)
+	return fmt.Sprintf("%s %3d%% %s", bar, percent, label)
+}
+
+func renderBar(filled int) string {
+	return Title.Render(strings.Repeat("█", filled)) +
+		MutedText.Render(strings.Repeat("░", progressBarWidth-filled))
+}